@@ -0,0 +1,396 @@
+// api/transmitter/transmitter.proto
+// общий контракт передатчика: код для клиента и сервера генерируется только отсюда
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: transmitter/transmitter.proto
+
+package transmitter
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TransmitterService_StreamData_FullMethodName    = "/transmitter.TransmitterService/StreamData"
+	TransmitterService_Stream_FullMethodName        = "/transmitter.TransmitterService/Stream"
+	TransmitterService_Connect_FullMethodName       = "/transmitter.TransmitterService/Connect"
+	TransmitterService_Subscribe_FullMethodName     = "/transmitter.TransmitterService/Subscribe"
+	TransmitterService_ListSessions_FullMethodName  = "/transmitter.TransmitterService/ListSessions"
+	TransmitterService_GetSession_FullMethodName    = "/transmitter.TransmitterService/GetSession"
+	TransmitterService_EndSession_FullMethodName    = "/transmitter.TransmitterService/EndSession"
+	TransmitterService_CreateSession_FullMethodName = "/transmitter.TransmitterService/CreateSession"
+)
+
+// TransmitterServiceClient is the client API for TransmitterService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TransmitterServiceClient interface {
+	StreamData(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Transmission], error)
+	Stream(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamMessage], error)
+	Connect(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ClientMessage, StreamMessage], error)
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamMessage], error)
+	// управление сессиями сервера
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	GetSession(ctx context.Context, in *GetSessionRequest, opts ...grpc.CallOption) (*SessionInfo, error)
+	EndSession(ctx context.Context, in *EndSessionRequest, opts ...grpc.CallOption) (*Empty, error)
+	CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*SessionInfo, error)
+}
+
+type transmitterServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTransmitterServiceClient(cc grpc.ClientConnInterface) TransmitterServiceClient {
+	return &transmitterServiceClient{cc}
+}
+
+func (c *transmitterServiceClient) StreamData(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Transmission], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TransmitterService_ServiceDesc.Streams[0], TransmitterService_StreamData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Empty, Transmission]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TransmitterService_StreamDataClient = grpc.ServerStreamingClient[Transmission]
+
+func (c *transmitterServiceClient) Stream(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TransmitterService_ServiceDesc.Streams[1], TransmitterService_Stream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRequest, StreamMessage]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TransmitterService_StreamClient = grpc.ServerStreamingClient[StreamMessage]
+
+func (c *transmitterServiceClient) Connect(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ClientMessage, StreamMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TransmitterService_ServiceDesc.Streams[2], TransmitterService_Connect_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ClientMessage, StreamMessage]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TransmitterService_ConnectClient = grpc.BidiStreamingClient[ClientMessage, StreamMessage]
+
+func (c *transmitterServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TransmitterService_ServiceDesc.Streams[3], TransmitterService_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, StreamMessage]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TransmitterService_SubscribeClient = grpc.ServerStreamingClient[StreamMessage]
+
+func (c *transmitterServiceClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, TransmitterService_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transmitterServiceClient) GetSession(ctx context.Context, in *GetSessionRequest, opts ...grpc.CallOption) (*SessionInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionInfo)
+	err := c.cc.Invoke(ctx, TransmitterService_GetSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transmitterServiceClient) EndSession(ctx context.Context, in *EndSessionRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, TransmitterService_EndSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transmitterServiceClient) CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*SessionInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionInfo)
+	err := c.cc.Invoke(ctx, TransmitterService_CreateSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TransmitterServiceServer is the server API for TransmitterService service.
+// All implementations must embed UnimplementedTransmitterServiceServer
+// for forward compatibility.
+type TransmitterServiceServer interface {
+	StreamData(*Empty, grpc.ServerStreamingServer[Transmission]) error
+	Stream(*StreamRequest, grpc.ServerStreamingServer[StreamMessage]) error
+	Connect(grpc.BidiStreamingServer[ClientMessage, StreamMessage]) error
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[StreamMessage]) error
+	// управление сессиями сервера
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	GetSession(context.Context, *GetSessionRequest) (*SessionInfo, error)
+	EndSession(context.Context, *EndSessionRequest) (*Empty, error)
+	CreateSession(context.Context, *CreateSessionRequest) (*SessionInfo, error)
+	mustEmbedUnimplementedTransmitterServiceServer()
+}
+
+// UnimplementedTransmitterServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTransmitterServiceServer struct{}
+
+func (UnimplementedTransmitterServiceServer) StreamData(*Empty, grpc.ServerStreamingServer[Transmission]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamData not implemented")
+}
+func (UnimplementedTransmitterServiceServer) Stream(*StreamRequest, grpc.ServerStreamingServer[StreamMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method Stream not implemented")
+}
+func (UnimplementedTransmitterServiceServer) Connect(grpc.BidiStreamingServer[ClientMessage, StreamMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
+func (UnimplementedTransmitterServiceServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[StreamMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedTransmitterServiceServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedTransmitterServiceServer) GetSession(context.Context, *GetSessionRequest) (*SessionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSession not implemented")
+}
+func (UnimplementedTransmitterServiceServer) EndSession(context.Context, *EndSessionRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EndSession not implemented")
+}
+func (UnimplementedTransmitterServiceServer) CreateSession(context.Context, *CreateSessionRequest) (*SessionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
+}
+func (UnimplementedTransmitterServiceServer) mustEmbedUnimplementedTransmitterServiceServer() {}
+func (UnimplementedTransmitterServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeTransmitterServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TransmitterServiceServer will
+// result in compilation errors.
+type UnsafeTransmitterServiceServer interface {
+	mustEmbedUnimplementedTransmitterServiceServer()
+}
+
+func RegisterTransmitterServiceServer(s grpc.ServiceRegistrar, srv TransmitterServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTransmitterServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TransmitterService_ServiceDesc, srv)
+}
+
+func _TransmitterService_StreamData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransmitterServiceServer).StreamData(m, &grpc.GenericServerStream[Empty, Transmission]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TransmitterService_StreamDataServer = grpc.ServerStreamingServer[Transmission]
+
+func _TransmitterService_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransmitterServiceServer).Stream(m, &grpc.GenericServerStream[StreamRequest, StreamMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TransmitterService_StreamServer = grpc.ServerStreamingServer[StreamMessage]
+
+func _TransmitterService_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TransmitterServiceServer).Connect(&grpc.GenericServerStream[ClientMessage, StreamMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TransmitterService_ConnectServer = grpc.BidiStreamingServer[ClientMessage, StreamMessage]
+
+func _TransmitterService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransmitterServiceServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, StreamMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TransmitterService_SubscribeServer = grpc.ServerStreamingServer[StreamMessage]
+
+func _TransmitterService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransmitterServiceServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransmitterService_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransmitterServiceServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransmitterService_GetSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransmitterServiceServer).GetSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransmitterService_GetSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransmitterServiceServer).GetSession(ctx, req.(*GetSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransmitterService_EndSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EndSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransmitterServiceServer).EndSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransmitterService_EndSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransmitterServiceServer).EndSession(ctx, req.(*EndSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransmitterService_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransmitterServiceServer).CreateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransmitterService_CreateSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransmitterServiceServer).CreateSession(ctx, req.(*CreateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TransmitterService_ServiceDesc is the grpc.ServiceDesc for TransmitterService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TransmitterService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "transmitter.TransmitterService",
+	HandlerType: (*TransmitterServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSessions",
+			Handler:    _TransmitterService_ListSessions_Handler,
+		},
+		{
+			MethodName: "GetSession",
+			Handler:    _TransmitterService_GetSession_Handler,
+		},
+		{
+			MethodName: "EndSession",
+			Handler:    _TransmitterService_EndSession_Handler,
+		},
+		{
+			MethodName: "CreateSession",
+			Handler:    _TransmitterService_CreateSession_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamData",
+			Handler:       _TransmitterService_StreamData_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Stream",
+			Handler:       _TransmitterService_Stream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Connect",
+			Handler:       _TransmitterService_Connect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _TransmitterService_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "transmitter/transmitter.proto",
+}
@@ -0,0 +1,392 @@
+// github.com/lonmouth/alien_wave/client/cmd/client/commands.go
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+	pg "github.com/lonmouth/alien_wave/client/internal/infrastructure/postgres"
+)
+
+// подкоманда migrate: применяет миграции схемы и завершается
+func migrateCmd(args []string) error {
+	cfg, err := loadConfig("migrate", args, nil)
+	if err != nil {
+		return err
+	}
+
+	db := initDatabase(cfg.PostgresDSN)
+	defer closeDatabase(db)
+
+	if err := pg.Migrate(db, cfg.SeverityScale()); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+	fmt.Println("Database migration completed successfully")
+	return nil
+}
+
+// флаги выборки аномалий, общие для query и export
+type filterFlags struct {
+	session string
+	since   time.Duration
+	from    string
+	to      string
+	limit   int
+
+	minSeverity string
+	sort        string
+	channel     string
+}
+
+func (f *filterFlags) bind(fs *flag.FlagSet, limit int) {
+	fs.StringVar(&f.session, "session", "", "только аномалии указанной сессии")
+	fs.DurationVar(&f.since, "since", 0, "только аномалии за последний период (например, 1h)")
+	fs.StringVar(&f.from, "from", "", "начало интервала в формате RFC3339")
+	fs.StringVar(&f.to, "to", "", "конец интервала в формате RFC3339")
+	fs.IntVar(&f.limit, "limit", limit, "максимальное количество записей (0 - без ограничений)")
+	fs.StringVar(&f.minSeverity, "min-severity", "", "только аномалии не ниже уровня: minor, major или critical")
+	fs.StringVar(&f.sort, "sort", "time", "порядок вывода: time (новые первыми) или severity (важные первыми)")
+	fs.StringVar(&f.channel, "channel", "", "только аномалии канала: frequency, имя канала или joint")
+}
+
+func (f *filterFlags) filter() (domain.AnomalyFilter, error) {
+	filter := domain.AnomalyFilter{SessionID: f.session, Limit: f.limit, Channel: f.channel}
+	if f.since > 0 {
+		filter.From = time.Now().Add(-f.since)
+	}
+	if f.from != "" {
+		t, err := time.Parse(time.RFC3339, f.from)
+		if err != nil {
+			return filter, fmt.Errorf("invalid -from: %w", err)
+		}
+		filter.From = t
+	}
+	if f.to != "" {
+		t, err := time.Parse(time.RFC3339, f.to)
+		if err != nil {
+			return filter, fmt.Errorf("invalid -to: %w", err)
+		}
+		filter.To = t
+	}
+	if f.minSeverity != "" {
+		s, err := domain.ParseSeverity(f.minSeverity)
+		if err != nil {
+			return filter, fmt.Errorf("invalid -min-severity: %w", err)
+		}
+		filter.MinSeverity = s
+	}
+	switch f.sort {
+	case "time":
+	case "severity":
+		filter.BySeverity = true
+	default:
+		return filter, fmt.Errorf("invalid -sort %q: want time or severity", f.sort)
+	}
+	return filter, nil
+}
+
+// подкоманда query: выводит аномалии таблицей
+func queryCmd(args []string) error {
+	var ff filterFlags
+	cfg, err := loadConfig("query", args, func(fs *flag.FlagSet) { ff.bind(fs, 50) })
+	if err != nil {
+		return err
+	}
+	filter, err := ff.filter()
+	if err != nil {
+		return err
+	}
+
+	db := initDatabase(cfg.PostgresDSN)
+	defer closeDatabase(db)
+
+	anomalies, err := pg.NewReader(db).Find(filter)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SESSION\tTIMESTAMP\tCHANNEL\tVALUE\tMEAN\tSTD\tK\tZ\tSEVERITY")
+	for _, a := range anomalies {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.2f\t%.2f\t%.2f\t%g\t%+.2f\t%s\n",
+			a.SessionID, a.Timestamp.Format(time.RFC3339), a.ChannelName(), a.Frequency, a.ExpectedMean, a.ExpectedSTD, a.K,
+			a.ZScore, a.Severity)
+	}
+	return w.Flush()
+}
+
+// подкоманда incidents: выводит эпизоды аномалий таблицей
+func incidentsCmd(args []string) error {
+	var ff filterFlags
+	cfg, err := loadConfig("incidents", args, func(fs *flag.FlagSet) { ff.bind(fs, 50) })
+	if err != nil {
+		return err
+	}
+	filter, err := ff.filter()
+	if err != nil {
+		return err
+	}
+
+	db := initDatabase(cfg.PostgresDSN)
+	defer closeDatabase(db)
+
+	incidents, err := pg.NewReader(db).Incidents(filter)
+	if err != nil {
+		return fmt.Errorf("incidents query failed: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSESSION\tSTARTED\tENDED\tPOINTS\tPEAK\tPEAK σ\tDIRECTION\tSTATE")
+	for _, i := range incidents {
+		state := "open"
+		if i.Closed {
+			state = "closed"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%.2f\t%.2f\t%s\t%s\n",
+			i.ID, i.SessionID, i.StartedAt.Format(time.RFC3339), i.EndedAt.Format(time.RFC3339),
+			i.Points, i.PeakFrequency, i.PeakDeviation, i.Direction, state)
+	}
+	return w.Flush()
+}
+
+// подкоманда changepoints: выводит изменения распределения сессий таблицей
+func changePointsCmd(args []string) error {
+	var ff filterFlags
+	cfg, err := loadConfig("changepoints", args, func(fs *flag.FlagSet) { ff.bind(fs, 50) })
+	if err != nil {
+		return err
+	}
+	filter, err := ff.filter()
+	if err != nil {
+		return err
+	}
+
+	db := initDatabase(cfg.PostgresDSN)
+	defer closeDatabase(db)
+
+	points, err := pg.NewReader(db).ChangePoints(filter)
+	if err != nil {
+		return fmt.Errorf("change points query failed: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSESSION\tKIND\tSTARTED\tDETECTED\tPOINTS\tμ BEFORE\tμ AFTER\tσ BEFORE\tσ AFTER\tRETRAINED")
+	for _, cp := range points {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\t%.2f\t%.2f\t%.2f\t%.2f\t%t\n",
+			cp.ID, cp.SessionID, cp.Kind, cp.StartedAt.Format(time.RFC3339), cp.DetectedAt.Format(time.RFC3339),
+			cp.Points, cp.BeforeMean, cp.AfterMean, cp.BeforeSTD, cp.AfterSTD, cp.Retrained)
+	}
+	return w.Flush()
+}
+
+// подкоманда events: выводит события потока сессий таблицей
+func eventsCmd(args []string) error {
+	var ff filterFlags
+	cfg, err := loadConfig("events", args, func(fs *flag.FlagSet) { ff.bind(fs, 50) })
+	if err != nil {
+		return err
+	}
+	filter, err := ff.filter()
+	if err != nil {
+		return err
+	}
+
+	db := initDatabase(cfg.PostgresDSN)
+	defer closeDatabase(db)
+
+	events, err := pg.NewReader(db).SessionEvents(filter)
+	if err != nil {
+		return fmt.Errorf("session events query failed: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSESSION\tKIND\tAT\tSEQ\tEXPECTED\tMISSING\tDETAIL")
+	for _, e := range events {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			e.ID, e.SessionID, e.Kind, e.At.Format(time.RFC3339Nano), e.Seq, e.Expected, e.Count, e.Detail)
+	}
+	return w.Flush()
+}
+
+// подкоманда export: выгружает аномалии в CSV или JSON
+func exportCmd(args []string) error {
+	var (
+		ff     filterFlags
+		format string
+		output string
+	)
+	cfg, err := loadConfig("export", args, func(fs *flag.FlagSet) {
+		ff.bind(fs, 0)
+		fs.StringVar(&format, "format", "csv", "формат выгрузки: csv или json")
+		fs.StringVar(&output, "o", "", "файл для выгрузки (по умолчанию stdout)")
+	})
+	if err != nil {
+		return err
+	}
+	if format != "csv" && format != "json" {
+		return fmt.Errorf("unknown export format %q", format)
+	}
+	filter, err := ff.filter()
+	if err != nil {
+		return err
+	}
+
+	db := initDatabase(cfg.PostgresDSN)
+	defer closeDatabase(db)
+
+	anomalies, err := pg.NewReader(db).Find(filter)
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if format == "json" {
+		return writeJSON(out, anomalies)
+	}
+	return writeCSV(out, anomalies)
+}
+
+func writeCSV(out io.Writer, anomalies []domain.Anomaly) error {
+	w := csv.NewWriter(out)
+	header := []string{
+		"session_id", "timestamp", "frequency", "expected_mean", "expected_std", "k",
+		"z_score", "direction", "severity", "algorithm", "algorithm_version", "incident_id", "provisional",
+		"channel",
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for _, a := range anomalies {
+		err := w.Write([]string{
+			a.SessionID,
+			a.Timestamp.Format(time.RFC3339Nano),
+			strconv.FormatFloat(a.Frequency, 'g', -1, 64),
+			strconv.FormatFloat(a.ExpectedMean, 'g', -1, 64),
+			strconv.FormatFloat(a.ExpectedSTD, 'g', -1, 64),
+			strconv.FormatFloat(a.K, 'g', -1, 64),
+			strconv.FormatFloat(a.ZScore, 'g', -1, 64),
+			string(a.Direction),
+			a.Severity.String(),
+			a.Algorithm,
+			a.AlgorithmVersion,
+			strconv.FormatUint(a.IncidentID, 10),
+			strconv.FormatBool(a.Provisional),
+			a.ChannelName(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func writeJSON(out io.Writer, anomalies []domain.Anomaly) error {
+	type record struct {
+		SessionID    string    `json:"session_id"`
+		Channel      string    `json:"channel"`
+		Timestamp    time.Time `json:"timestamp"`
+		Frequency    float64   `json:"frequency"`
+		ExpectedMean float64   `json:"expected_mean"`
+		ExpectedSTD  float64   `json:"expected_std"`
+		K            float64   `json:"k"`
+		ZScore       *float64  `json:"z_score"` // null, если σ была нулевой
+		Direction    string    `json:"direction"`
+		Severity     string    `json:"severity"`
+		Algorithm    string    `json:"algorithm"`
+		AlgVersion   string    `json:"algorithm_version"`
+		IncidentID   uint64    `json:"incident_id,omitempty"`
+		Provisional  bool      `json:"provisional"`
+	}
+	records := make([]record, 0, len(anomalies))
+	for _, a := range anomalies {
+		records = append(records, record{
+			SessionID:    a.SessionID,
+			Channel:      a.ChannelName(),
+			Timestamp:    a.Timestamp,
+			Frequency:    a.Frequency,
+			ExpectedMean: a.ExpectedMean,
+			ExpectedSTD:  a.ExpectedSTD,
+			K:            a.K,
+			ZScore:       finite(a.ZScore),
+			Direction:    string(a.Direction),
+			Severity:     a.Severity.String(),
+			Algorithm:    a.Algorithm,
+			AlgVersion:   a.AlgorithmVersion,
+			IncidentID:   a.IncidentID,
+			Provisional:  a.Provisional,
+		})
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
+
+// JSON не поддерживает бесконечность, поэтому такие значения выводятся как null
+func finite(f float64) *float64 {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil
+	}
+	return &f
+}
+
+// подкоманда stats: сводка по каждой сессии
+func statsCmd(args []string) error {
+	cfg, err := loadConfig("stats", args, nil)
+	if err != nil {
+		return err
+	}
+
+	db := initDatabase(cfg.PostgresDSN)
+	defer closeDatabase(db)
+
+	sessions, err := pg.NewReader(db).Sessions()
+	if err != nil {
+		return fmt.Errorf("stats failed: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SESSION\tANOMALIES\tFIRST\tLAST\tMIN FREQ\tMAX FREQ\tMEAN\tSTD")
+	for _, s := range sessions {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%.2f\t%.2f\t%.2f\t%.2f\n",
+			s.SessionID, s.Anomalies,
+			s.FirstSeen.Format(time.RFC3339), s.LastSeen.Format(time.RFC3339),
+			s.MinFrequency, s.MaxFrequency, s.ExpectedMean, s.ExpectedSTD)
+	}
+	return w.Flush()
+}
+
+// подкоманда config: пока поддерживает только config print
+func configCmd(args []string) error {
+	if len(args) == 0 || args[0] != "print" {
+		return fmt.Errorf("usage: client config print [flags]")
+	}
+
+	// конфигурация печатается и при ошибках проверки, чтобы было видно, откуда взялось значение
+	cfg, err := loadConfig("config print", args[1:], nil)
+	if cfg == nil {
+		return err
+	}
+	for _, kv := range cfg.Redacted() {
+		fmt.Printf("%s=%s\n", kv[0], kv[1])
+	}
+	return err
+}
@@ -2,7 +2,9 @@
 
 // ├── cmd
 // │   └── client
-// │       └── main.go
+// │       ├── main.go     — разбор подкоманд
+// │       ├── run.go      — подкоманда run (обработка потока)
+// │       └── commands.go — служебные подкоманды
 // ├── proto
 // │   └── transmitter.proto
 // └── internal
@@ -22,199 +24,99 @@
 package main
 
 import (
-	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"os"
-	"os/signal"
-	"syscall"
 
-	"github.com/lonmouth/alien_wave/client/internal/application"
 	"github.com/lonmouth/alien_wave/client/internal/config"
-	"github.com/lonmouth/alien_wave/client/internal/infrastructure/grpc"
-  pg "github.com/lonmouth/alien_wave/client/internal/infrastructure/postgres"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 )
 
-func main() {
-	// Инициализация конфигурации
-	cfg := config.Load()
-
-	// Настройка системы
-	system := setupSystem(cfg)
-	defer teardownSystem(system)
-
-	// Запуск обработки данных
-	dataProcessor := startDataProcessing(system)
-	defer dataProcessor.Stop()
-
-	// Ожидание сигналов завершения
-	waitForShutdownSignal(system, cfg)
-}
+const usage = `Usage: client <command> [flags]
 
-// SystemComponents содержит все системные компоненты
-type SystemComponents struct {
-	DB         *gorm.DB
-	GRPCClient *grpc.Client
-	Detector   *application.Detector
-	Cancel     context.CancelFunc
-}
+Commands:
+  run           получать поток и обнаруживать аномалии (по умолчанию)
+  migrate       применить миграции схемы базы данных
+  query         вывести аномалии из репозитория
+  incidents     вывести инциденты (эпизоды подряд идущих аномалий)
+  changepoints  вывести изменения распределения сессий
+  events        вывести события потока сессий (пропуски, повторы, нарушения порядка)
+  export        выгрузить аномалии в CSV или JSON
+  stats         сводка по сессиям
+  config print  показать действующую конфигурацию (секреты скрыты)
 
-// setupSystem инициализирует все системные компоненты
-func setupSystem(cfg *config.Config) *SystemComponents {
-	// Инициализация контекста с возможностью отмены
-	_, cancel := context.WithCancel(context.Background())
-
-	// Подключение к БД
-	db := initDatabase(cfg.PostgresDSN)
-
-	// Инициализация репозитория
-	repo := pg.NewRepository(db)
-
-	// Подключение к gRPC серверу
-	gClient := initGRPCClient(cfg.GRPCServerAddr)
-
-	// Создание детектора аномалий
-	detector := application.NewDetector(
-		repo,
-		cfg.AnomalyK,
-		cfg.TrainSamples,
-		cfg.LogInterval,
-	)
-
-	return &SystemComponents{
-		DB:         db,
-		GRPCClient: gClient,
-		Detector:   detector,
-		Cancel:     cancel,
-	}
-}
+Флаги команд переопределяют переменные окружения; подробнее: client <command> -h
+`
 
-// teardownSystem корректно освобождает ресурсы
-func teardownSystem(s *SystemComponents) {
-	// Закрытие gRPC соединения
-	if err := s.GRPCClient.Close(); err != nil {
-		log.Printf("GRPC close error: %v", err)
+func main() {
+	args := os.Args[1:]
+	name := "run" // без подкоманды сохраняется прежнее поведение
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		name, args = args[0], args[1:]
 	}
 
-	// Закрытие подключения к БД
-	if sqlDB, err := s.DB.DB(); err == nil {
-		if err := sqlDB.Close(); err != nil {
-			log.Printf("DB close error: %v", err)
-		}
+	var err error
+	switch name {
+	case "run":
+		err = runCmd(args)
+	case "migrate":
+		err = migrateCmd(args)
+	case "query":
+		err = queryCmd(args)
+	case "incidents":
+		err = incidentsCmd(args)
+	case "changepoints":
+		err = changePointsCmd(args)
+	case "events":
+		err = eventsCmd(args)
+	case "export":
+		err = exportCmd(args)
+	case "stats":
+		err = statsCmd(args)
+	case "config":
+		err = configCmd(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", name, usage)
+		os.Exit(2)
 	}
-}
 
-// initDatabase инициализирует подключение к PostgreSQL
-func initDatabase(dsn string) *gorm.DB {
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	if err != nil {
-		log.Fatal("DB connection error:", err)
+	if err == flag.ErrHelp {
+		os.Exit(0)
 	}
-
-	// Проверка подключения
-	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatal("DB access error:", err)
+		log.Fatal(err)
 	}
-
-	if err := sqlDB.Ping(); err != nil {
-		log.Fatal("DB ping failed:", err)
-	}
-
-	return db
 }
 
-// initGRPCClient создает gRPC клиент
-func initGRPCClient(addr string) *grpc.Client {
-	client, err := grpc.NewClient(addr)
-	if err != nil {
-		log.Fatal("gRPC connection error:", err)
+// загружает конфигурацию из окружения, переопределяет ее флагами подкоманды и проверяет результат.
+// bind позволяет подкоманде зарегистрировать собственные флаги. При ошибке проверки конфигурация
+// тоже возвращается, а ошибка перечисляет все найденные проблемы: разбора флагов, окружения
+// (кроме переменных, переопределенных заданными флагами) и проверки значений
+func loadConfig(name string, args []string, bind func(fs *flag.FlagSet)) (*config.Config, error) {
+	cfg, loadErr := config.Load()
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	cfg.BindFlags(fs)
+	if bind != nil {
+		bind(fs)
 	}
-	return client
-}
-
-// DataProcessor управляет обработкой данных
-type DataProcessor struct {
-	ctx    context.Context
-	cancel context.CancelFunc
-	done   chan struct{}
-}
-
-// startDataProcessing запускает обработку потока данных
-func startDataProcessing(s *SystemComponents) *DataProcessor {
-	ctx, cancel := context.WithCancel(context.Background())
-	done := make(chan struct{})
-
-	// Получение потока данных
-	stream, err := s.GRPCClient.Stream()
-	if err != nil {
-		log.Fatal("Stream error:", err)
-	}
-
-	go func() {
-		defer close(done)
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			default:
-				data, err := stream.Recv()
-				if err != nil {
-					log.Printf("Stream error: %v", err)
-					return
-				}
-				s.Detector.Process(data)
-			}
-		}
-	}()
-
-	return &DataProcessor{
-		ctx:    ctx,
-		cancel: cancel,
-		done:   done,
+	parseErr := fs.Parse(args)
+	if parseErr == flag.ErrHelp {
+		return nil, parseErr
 	}
-}
-
-// Stop останавливает обработку данных
-func (dp *DataProcessor) Stop() {
-	dp.cancel()
-	<-dp.done
-}
-
-// waitForShutdownSignal обрабатывает сигналы завершения
-func waitForShutdownSignal(s *SystemComponents, cfg *config.Config) {
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-
-	// Ожидание сигнала или ошибки
-	select {
-	case sig := <-sigCh:
-		log.Printf("Received signal: %v", sig)
-	case <-s.Detector.ShutdownChannel():
-		log.Println("Detector initiated shutdown")
-	}
-
-	// Инициируем завершение работы
-	s.Cancel()
-
-	// Graceful shutdown
-	shutdownCtx, cancel := context.WithTimeout(
-		context.Background(),
-		cfg.ShutdownTimeout,
-	)
-	defer cancel()
 
-	if err := s.Detector.Shutdown(shutdownCtx); err != nil {
-		log.Printf("Graceful shutdown failed: %v", err)
-	} else {
-		log.Println("Shutdown completed successfully")
+	if err := errors.Join(parseErr, config.WithoutOverridden(loadErr, fs), cfg.Validate()); err != nil {
+		return cfg, fmt.Errorf("invalid configuration:\n%w", err)
 	}
+	return cfg, nil
 }
 
 // SELECT COUNT(*) FROM anomalies;
 
-// SELECT 
+// SELECT
 //     session_id,
 //     ROUND(frequency::numeric, 2) AS frequency,
 //     timestamp,
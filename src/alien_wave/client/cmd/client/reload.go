@@ -0,0 +1,79 @@
+// github.com/lonmouth/alien_wave/client/cmd/client/reload.go
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/lonmouth/alien_wave/client/internal/config"
+)
+
+// watchReload перечитывает конфигурацию по SIGHUP или при изменении .env и атомарно применяет
+// параметры, которые безопасно менять на лету. Обученная статистика сессии при этом сохраняется
+func watchReload(s *SystemComponents, cfg *config.Config, args []string) {
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+
+	var fileCh <-chan struct{}
+	if cfg.ReloadWatch > 0 {
+		fileCh = config.WatchFile(s.Ctx, config.EnvFile, cfg.ReloadWatch)
+	}
+
+	go func() {
+		defer signal.Stop(hupCh)
+
+		version := s.Detector.Settings().Version
+		for {
+			select {
+			case <-s.Ctx.Done():
+				return
+			case <-hupCh:
+				log.Println("Received SIGHUP, reloading configuration")
+			case _, ok := <-fileCh:
+				if !ok {
+					return
+				}
+				log.Printf("%s changed, reloading configuration", config.EnvFile)
+			}
+
+			// флаги командной строки применяются повторно и по-прежнему переопределяют окружение
+			next, err := loadConfig("run", args, nil)
+			if err != nil {
+				log.Printf("Configuration reload rejected, keeping v%d: %v", version, err)
+				continue
+			}
+			if changed := cfg.RestartRequired(next); len(changed) > 0 {
+				log.Printf("Restart required to apply: %s", strings.Join(changed, ", "))
+			}
+
+			// правила, версия и настройки уведомлений проверяются первыми: при ошибке не меняется ничего
+			rules, err := loadRules(next)
+			if err != nil {
+				log.Printf("Configuration reload rejected, keeping v%d: %v", version, err)
+				continue
+			}
+			detector, err := versioned(s.DB, detectorSettings(next))
+			if err != nil {
+				log.Printf("Configuration reload rejected, keeping v%d: %v", version, err)
+				continue
+			}
+			settings, err := webhookSettings(next)
+			if err == nil {
+				err = s.Notifier.Update(settings)
+			}
+			if err != nil {
+				log.Printf("Configuration reload rejected, keeping v%d: %v", version, err)
+				continue
+			}
+
+			version = detector.Version
+			s.Rules.SetRules(rules)
+			s.Detector.Apply(detector)
+			log.Printf("Configuration v%d applied: K=%g, log interval=%d, incident gap=%d, %d alert rules",
+				version, next.AnomalyK, next.LogInterval, next.IncidentGap, len(rules))
+		}
+	}()
+}
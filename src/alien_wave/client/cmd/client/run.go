@@ -0,0 +1,319 @@
+// github.com/lonmouth/alien_wave/client/cmd/client/run.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lonmouth/alien_wave/client/internal/application"
+	"github.com/lonmouth/alien_wave/client/internal/config"
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+	"github.com/lonmouth/alien_wave/client/internal/infrastructure/admin"
+	"github.com/lonmouth/alien_wave/client/internal/infrastructure/grpc"
+	"github.com/lonmouth/alien_wave/client/internal/infrastructure/metrics"
+	pg "github.com/lonmouth/alien_wave/client/internal/infrastructure/postgres"
+	"github.com/lonmouth/alien_wave/client/internal/infrastructure/webhook"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// подкоманда run: получение потока данных и обнаружение аномалий
+func runCmd(args []string) error {
+	// Инициализация конфигурации
+	cfg, err := loadConfig("run", args, nil)
+	if err != nil {
+		return err
+	}
+
+	// Настройка системы
+	system := setupSystem(cfg)
+	defer teardownSystem(system)
+
+	// Запуск обработки данных
+	dataProcessor := startDataProcessing(system, cfg.SubscribeSession)
+
+	// Перезагрузка параметров по SIGHUP или при изменении .env
+	watchReload(system, cfg, args)
+
+	// Ожидание сигналов завершения
+	waitForShutdownSignal(system, dataProcessor, cfg)
+	return nil
+}
+
+// SystemComponents содержит все системные компоненты
+type SystemComponents struct {
+	DB         *gorm.DB
+	GRPCClient *grpc.Client
+	Detector   *application.Detector
+	Admin      *admin.Server           // nil, если admin API отключен
+	Notifier   *webhook.Notifier       // webhook-уведомления об аномалиях
+	Rules      *application.RuleEngine // правила алертинга
+	Ctx        context.Context         // отменяется при завершении работы
+	Cancel     context.CancelFunc
+}
+
+// setupSystem инициализирует все системные компоненты
+func setupSystem(cfg *config.Config) *SystemComponents {
+	// Инициализация контекста с возможностью отмены
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Подключение к БД
+	db := initDatabase(cfg.PostgresDSN)
+
+	// Инициализация репозитория
+	repo := pg.NewRepository(db, cfg.SeverityScale())
+
+	// Подключение к gRPC серверу
+	gClient := initGRPCClient(cfg.GRPCServerAddr)
+
+	// Создание детектора аномалий
+	settings, err := versioned(db, detectorSettings(cfg))
+	if err != nil {
+		log.Fatal("Config version error:", err)
+	}
+	detector := application.NewDetector(repo, settings)
+
+	// Webhook-уведомления об аномалиях
+	hooks, err := webhookSettings(cfg)
+	if err != nil {
+		log.Fatal("Webhook setup error:", err)
+	}
+	notifier, err := webhook.New(hooks)
+	if err != nil {
+		log.Fatal("Webhook setup error:", err)
+	}
+	detector.SetNotifier(notifier)
+
+	// Правила алертинга поверх потока аномалий
+	rules, err := loadRules(cfg)
+	if err != nil {
+		log.Fatal("Alert rules error:", err)
+	}
+	engine := application.NewRuleEngine(rules, notifier)
+	detector.SetObserver(engine)
+	go engine.Run(ctx.Done(), time.Second)
+
+	// Счетчики детектора в формате Prometheus
+	prom := metrics.New()
+	detector.SetMetrics(prom)
+
+	// Admin API для просмотра состояния детектора
+	var adminSrv *admin.Server
+	if cfg.AdminAddr != "" {
+		adminSrv = admin.NewServer(cfg.AdminAddr, detector)
+		adminSrv.Handle("GET /metrics", prom.Handler())
+		if err := adminSrv.Start(); err != nil {
+			log.Fatal("Admin API error:", err)
+		}
+	}
+
+	return &SystemComponents{
+		DB:         db,
+		GRPCClient: gClient,
+		Detector:   detector,
+		Admin:      adminSrv,
+		Notifier:   notifier,
+		Rules:      engine,
+		Ctx:        ctx,
+		Cancel:     cancel,
+	}
+}
+
+// detectorSettings собирает параметры детектора, которые меняются при перезагрузке конфигурации; версию задает versioned
+func detectorSettings(cfg *config.Config) application.Settings {
+	return application.Settings{
+		Checker:     cfg.Checker(),
+		WarmUp:      cfg.WarmUp(),
+		Training:    domain.TrainingPolicy(cfg.TrainingPolicy),
+		Robust:      cfg.Robust(),
+		LogInterval: cfg.LogInterval,
+		IncidentGap: cfg.IncidentGap,
+		Severity:    cfg.SeverityScale(),
+		Debounce:    cfg.Debounce(),
+		ChangePoint: cfg.ChangePoint(),
+		Joint:       cfg.JointDetection,
+	}
+}
+
+// versioned задает версию параметров детектора из таблицы config_versions: одинаковые параметры
+// получают одну версию и после перезапуска, поэтому по config_version аномалии можно найти ее параметры
+func versioned(db *gorm.DB, s application.Settings) (application.Settings, error) {
+	s.Version = 0
+	data, err := json.Marshal(s)
+	if err != nil {
+		return s, fmt.Errorf("encode detector settings: %w", err)
+	}
+	s.Version, err = pg.ConfigVersion(db, data)
+	return s, err
+}
+
+// webhookSettings собирает настройки уведомлений, читая файл шаблона
+func webhookSettings(cfg *config.Config) (webhook.Settings, error) {
+	w := cfg.Webhook
+	var tmpl string
+	if w.TemplateFile != "" {
+		data, err := os.ReadFile(w.TemplateFile)
+		if err != nil {
+			return webhook.Settings{}, err
+		}
+		tmpl = string(data)
+	}
+	return webhook.Settings{
+		URLs:       w.URLs,
+		Secret:     w.Secret,
+		Template:   tmpl,
+		Timeout:    w.Timeout,
+		MaxRetries: int(w.MaxRetries),
+		Backoff:    w.Backoff,
+		RatePerMin: w.RatePerMin,
+		Burst:      int(w.Burst),
+		GroupMax:   w.GroupMax,
+	}, nil
+}
+
+// loadRules читает правила алертинга, если файл задан
+func loadRules(cfg *config.Config) ([]application.Rule, error) {
+	if cfg.AlertRulesFile == "" {
+		return nil, nil
+	}
+	return application.LoadRules(cfg.AlertRulesFile)
+}
+
+// teardownSystem корректно освобождает ресурсы
+func teardownSystem(s *SystemComponents) {
+	// Закрытие gRPC соединения
+	if err := s.GRPCClient.Close(); err != nil {
+		log.Printf("GRPC close error: %v", err)
+	}
+
+	// Закрытие подключения к БД
+	closeDatabase(s.DB)
+}
+
+// closeDatabase закрывает пул соединений с БД
+func closeDatabase(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			log.Printf("DB close error: %v", err)
+		}
+	}
+}
+
+// initDatabase инициализирует подключение к PostgreSQL
+func initDatabase(dsn string) *gorm.DB {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		log.Fatal("DB connection error:", err)
+	}
+
+	// Проверка подключения
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal("DB access error:", err)
+	}
+
+	if err := sqlDB.Ping(); err != nil {
+		log.Fatal("DB ping failed:", err)
+	}
+
+	return db
+}
+
+// initGRPCClient создает gRPC клиент
+func initGRPCClient(addr string) *grpc.Client {
+	client, err := grpc.NewClient(addr)
+	if err != nil {
+		log.Fatal("gRPC connection error:", err)
+	}
+	return client
+}
+
+// DataProcessor управляет обработкой данных
+type DataProcessor struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startDataProcessing запускает обработку потока данных; subscribe - сессия сервера для подписки
+func startDataProcessing(s *SystemComponents, subscribe string) *DataProcessor {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Поток данных с подтверждениями; после обрыва соединения сессия продолжается.
+	// С SUBSCRIBE_SESSION клиент получает ту же сессию, что и другие подписчики
+	link := s.GRPCClient.Link(s.Detector)
+	if subscribe != "" {
+		link = s.GRPCClient.Subscribe(s.Detector, subscribe)
+	}
+	if s.Admin != nil {
+		s.Admin.SetLink(link)
+	}
+
+	go func() {
+		defer close(done)
+		link.Run(ctx)
+	}()
+
+	return &DataProcessor{
+		ctx:    ctx,
+		cancel: cancel,
+		done:   done,
+	}
+}
+
+// Stop останавливает обработку данных
+func (dp *DataProcessor) Stop() {
+	dp.cancel()
+	<-dp.done
+}
+
+// waitForShutdownSignal обрабатывает сигналы завершения. Поток данных останавливается до закрытия
+// уведомлений: аномалии, найденные во время остановки, успевают попасть в очередь webhook
+func waitForShutdownSignal(s *SystemComponents, dp *DataProcessor, cfg *config.Config) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	// Ожидание сигнала или ошибки
+	select {
+	case sig := <-sigCh:
+		log.Printf("Received signal: %v", sig)
+	case <-s.Detector.ShutdownChannel():
+		log.Println("Detector initiated shutdown")
+	}
+
+	// Инициируем завершение работы
+	s.Cancel()
+
+	// Детектор больше не получает точек и не вызывает Notify
+	dp.Stop()
+
+	// Graceful shutdown
+	shutdownCtx, cancel := context.WithTimeout(
+		context.Background(),
+		cfg.ShutdownTimeout,
+	)
+	defer cancel()
+
+	if s.Admin != nil {
+		if err := s.Admin.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Admin API shutdown error: %v", err)
+		}
+	}
+
+	if err := s.Notifier.Close(shutdownCtx); err != nil {
+		log.Printf("Webhook shutdown error: %v", err)
+	}
+
+	if err := s.Detector.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Graceful shutdown failed: %v", err)
+	} else {
+		log.Println("Shutdown completed successfully")
+	}
+}
@@ -0,0 +1,124 @@
+// github.com/lonmouth/alien_wave/client/e2e/e2e_test.go
+package e2e
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+)
+
+// равномерный генератор не выходит за μ±√3σ, поэтому при K=3 аномалии дают только внедренные точки
+var uniformArgs = []string{
+	"-send-interval", "2ms",
+	"-heartbeat-interval", "1s",
+	"-generator", "uniform",
+	"-mean-min", "10", "-mean-max", "10",
+	"-std-min", "1", "-std-max", "1",
+}
+
+// заменяет частоту точки seq на value
+func spike(seq uint64, value float64) func(*transmitter.Transmission) {
+	return func(p *transmitter.Transmission) {
+		if p.Seq == seq {
+			p.Frequency = value
+		}
+	}
+}
+
+func TestUniformStreamHasNoAnomalies(t *testing.T) {
+	h := New(t, Options{ServerArgs: uniformArgs})
+	link := h.Connect()
+
+	h.WaitPoints(link, 300)
+	if anomalies := h.Repo.Anomalies(); len(anomalies) != 0 {
+		t.Fatalf("got %d anomalies without injected points, first: %+v", len(anomalies), anomalies[0])
+	}
+}
+
+func TestInjectedSpikeIsDetected(t *testing.T) {
+	h := New(t, Options{ServerArgs: uniformArgs, Inject: spike(150, 30)})
+	link := h.Connect()
+
+	h.WaitAnomalies(1)
+	h.WaitPoints(link, 200)
+	anomalies := h.Repo.Anomalies()
+	if len(anomalies) != 1 {
+		t.Fatalf("got %d anomalies, want 1: %+v", len(anomalies), anomalies)
+	}
+	a := anomalies[0]
+	if a.SessionID != link.State().SessionID {
+		t.Errorf("anomaly session = %s, want %s", a.SessionID, link.State().SessionID)
+	}
+	if a.Frequency != 30 || a.Direction != domain.DirectionHigh || a.Severity != domain.SeverityCritical {
+		t.Errorf("anomaly = frequency %g, direction %s, severity %s; want 30, high, critical", a.Frequency, a.Direction, a.Severity)
+	}
+	if math.Abs(a.ExpectedMean-10) > 0.5 || math.Abs(a.ExpectedSTD-1) > 0.3 {
+		t.Errorf("baseline μ=%.3f σ=%.3f, want about μ=10 σ=1", a.ExpectedMean, a.ExpectedSTD)
+	}
+
+	inc, ok := h.Repo.Incident(a.IncidentID)
+	if !ok {
+		t.Fatalf("incident %d of the anomaly is not saved", a.IncidentID)
+	}
+	if inc.Points != 1 || inc.PeakFrequency != 30 {
+		t.Errorf("incident = %d points, peak %g; want 1 point, peak 30", inc.Points, inc.PeakFrequency)
+	}
+}
+
+func TestSessionEndIsRecorded(t *testing.T) {
+	args := append([]string{"-session-points", "150"}, uniformArgs...)
+	h := New(t, Options{ServerArgs: args, Inject: spike(140, -10)})
+	link := h.Connect()
+
+	a := h.WaitAnomalies(1)[0]
+	if a.Direction != domain.DirectionLow {
+		t.Errorf("anomaly direction = %s, want low", a.Direction)
+	}
+	// после завершения первой сессии поток продолжается новой
+	h.WaitFor("next session", func() bool {
+		id := link.State().SessionID
+		return id != "" && id != a.SessionID
+	})
+
+	var kinds []domain.SessionEventKind
+	for _, e := range h.Repo.SessionEvents() {
+		if e.SessionID != a.SessionID {
+			continue
+		}
+		kinds = append(kinds, e.Kind)
+		if e.Kind == domain.EventSessionEnd && e.Detail != "completed" {
+			t.Errorf("session end detail = %q, want completed", e.Detail)
+		}
+	}
+	if len(kinds) != 2 || kinds[0] != domain.EventSessionStart || kinds[1] != domain.EventSessionEnd {
+		t.Errorf("session events = %v, want [%s %s]", kinds, domain.EventSessionStart, domain.EventSessionEnd)
+	}
+}
+
+func TestSubscribedSessionAnomalies(t *testing.T) {
+	h := New(t, Options{ServerArgs: uniformArgs, Inject: spike(120, -5)})
+	id := h.CreateSession(&transmitter.GeneratorParameters{Model: "uniform", Mean: 3, Std: 0.5})
+	h.Subscribe(id) // сессию без потока Connect рассылает сам сервер
+
+	a := h.WaitAnomalies(1)[0]
+	if a.SessionID != id {
+		t.Errorf("anomaly session = %s, want %s", a.SessionID, id)
+	}
+	if a.Frequency != -5 || a.Direction != domain.DirectionLow {
+		t.Errorf("anomaly = frequency %g, direction %s; want -5, low", a.Frequency, a.Direction)
+	}
+	if math.Abs(a.ExpectedMean-3) > 0.25 {
+		t.Errorf("baseline μ=%.3f, want about 3", a.ExpectedMean)
+	}
+
+	info, err := h.Control.GetSession(context.Background(), &transmitter.GetSessionRequest{SessionId: id})
+	if err != nil {
+		t.Fatalf("get session: %v", err)
+	}
+	if info.Subscribers != 1 {
+		t.Errorf("session subscribers = %d, want 1", info.Subscribers)
+	}
+}
@@ -0,0 +1,26 @@
+module github.com/lonmouth/alien_wave/client/e2e
+
+go 1.23.5
+
+require (
+	github.com/lonmouth/alien_wave/api v0.0.0
+	github.com/lonmouth/alien_wave/client v0.0.0
+	github.com/lonmouth/alien_wave/server v0.0.0
+)
+
+require (
+	github.com/google/uuid v1.6.0 // indirect
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250115164207-1a7da9e5054f // indirect
+	google.golang.org/grpc v1.71.0 // indirect
+	google.golang.org/protobuf v1.36.5 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/lonmouth/alien_wave/api => ../../api
+	github.com/lonmouth/alien_wave/client => ..
+	github.com/lonmouth/alien_wave/server => ../../server
+)
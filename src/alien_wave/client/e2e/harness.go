@@ -0,0 +1,162 @@
+// github.com/lonmouth/alien_wave/client/e2e/harness.go
+
+// Package e2e собирает весь путь данных в одном процессе: настоящий сервер передатчика на bufconn,
+// клиент infrastructure/grpc и детектор с репозиторием в памяти. Тестам не нужны сетевой порт и PostgreSQL
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"github.com/lonmouth/alien_wave/client/internal/application"
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+	"github.com/lonmouth/alien_wave/client/internal/infrastructure/grpc"
+	"github.com/lonmouth/alien_wave/server/transmittertest"
+)
+
+// сколько WaitFor ждет выполнения условия
+const waitTimeout = 10 * time.Second
+
+// Options - параметры окружения теста
+type Options struct {
+	ServerArgs []string                      // флаги сервера, как у бинарника (-send-interval, -generator, ...)
+	Inject     transmittertest.Inject        // изменение точек перед отправкой (nil - точки генератора без изменений)
+	Settings   func(s *application.Settings) // изменение параметров детектора по умолчанию (может быть nil)
+}
+
+// Harness - сервер, клиент и детектор одного теста; все останавливается в t.Cleanup
+type Harness struct {
+	Server   *transmittertest.Server
+	Client   *grpc.Client
+	Control  transmitter.TransmitterServiceClient // унарные RPC управления сессиями
+	Detector *application.Detector
+	Repo     *Repository
+
+	t testing.TB
+}
+
+// DefaultSettings - параметры детектора для тестов: K=3, обучение по 100 точкам без проверки отклонений,
+// без подавления дребезга и обнаружения изменений распределения
+func DefaultSettings() application.Settings {
+	return application.Settings{
+		Checker:     *domain.NewAnomalyChecker(3),
+		WarmUp:      domain.WarmUp{MinSamples: 100},
+		Training:    domain.TrainingSuppress,
+		Robust:      domain.Robust{Estimator: domain.EstimatorMean},
+		LogInterval: 100,
+		IncidentGap: 5,
+		Severity:    domain.SeverityScale{Major: 3, Critical: 5},
+		Debounce:    domain.Debounce{ExitRatio: 1, ConfirmN: 1, ConfirmM: 1},
+		Version:     1,
+	}
+}
+
+// New запускает сервер и подключает к нему клиент и детектор. Поток данных начинают Connect или Subscribe
+func New(t testing.TB, opts Options) *Harness {
+	t.Helper()
+
+	srv, err := transmittertest.NewServer(opts.ServerArgs, opts.Inject)
+	if err != nil {
+		t.Fatalf("start server: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	client, err := grpc.NewClient(transmittertest.Target, srv.DialOptions()...)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	conn, err := srv.Dial()
+	if err != nil {
+		t.Fatalf("dial control connection: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	settings := DefaultSettings()
+	if opts.Settings != nil {
+		opts.Settings(&settings)
+	}
+	repo := NewRepository()
+	detector := application.NewDetector(repo, settings)
+	t.Cleanup(func() { detector.Shutdown(context.Background()) })
+
+	return &Harness{
+		Server:   srv,
+		Client:   client,
+		Control:  transmitter.NewTransmitterServiceClient(conn),
+		Detector: detector,
+		Repo:     repo,
+		t:        t,
+	}
+}
+
+// Connect запускает поток Connect в детектор; поток останавливается в t.Cleanup
+func (h *Harness) Connect() *grpc.Link {
+	return h.run(h.Client.Link(h.Detector))
+}
+
+// Subscribe запускает подписку детектора на сессию sessionID; подписка останавливается в t.Cleanup
+func (h *Harness) Subscribe(sessionID string) *grpc.Link {
+	return h.run(h.Client.Subscribe(h.Detector, sessionID))
+}
+
+func (h *Harness) run(link *grpc.Link) *grpc.Link {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		link.Run(ctx)
+	}()
+	h.t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return link
+}
+
+// CreateSession создает на сервере сессию с заданными параметрами генератора и возвращает ее ID
+func (h *Harness) CreateSession(params *transmitter.GeneratorParameters) string {
+	h.t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+	info, err := h.Control.CreateSession(ctx, &transmitter.CreateSessionRequest{Parameters: params})
+	if err != nil {
+		h.t.Fatalf("create session: %v", err)
+	}
+	return info.SessionId
+}
+
+// WaitFor ждет выполнения cond; если за waitTimeout условие не выполнено, тест завершается с описанием what
+func (h *Harness) WaitFor(what string, cond func() bool) {
+	h.t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			h.t.Fatalf("timed out after %v waiting for %s", waitTimeout, what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// WaitPoints ждет, пока link подтвердит точку seq своей текущей сессии
+func (h *Harness) WaitPoints(link *grpc.Link, seq uint64) {
+	h.t.Helper()
+	h.WaitFor("acknowledged points", func() bool { return link.State().Acked >= seq })
+}
+
+// WaitAnomalies ждет, пока в репозитории окажется не меньше n аномалий, и возвращает их
+func (h *Harness) WaitAnomalies(n int) []domain.Anomaly {
+	h.t.Helper()
+
+	var anomalies []domain.Anomaly
+	h.WaitFor("anomalies", func() bool {
+		anomalies = h.Repo.Anomalies()
+		return len(anomalies) >= n
+	})
+	return anomalies
+}
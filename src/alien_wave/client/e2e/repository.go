@@ -0,0 +1,99 @@
+// github.com/lonmouth/alien_wave/client/e2e/repository.go
+package e2e
+
+import (
+	"sync"
+
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+)
+
+// Repository хранит все, что сохраняет детектор, в памяти; заменяет PostgreSQL в тестах
+type Repository struct {
+	mu           sync.Mutex
+	anomalies    []domain.Anomaly
+	incidents    map[uint64]domain.Incident
+	changePoints []domain.ChangePoint
+	events       []domain.SessionEvent
+	nextID       uint64
+}
+
+func NewRepository() *Repository {
+	return &Repository{incidents: make(map[uint64]domain.Incident)}
+}
+
+func (r *Repository) Save(a domain.Anomaly) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.anomalies = append(r.anomalies, a)
+	return nil
+}
+
+// создает (ID == 0) или обновляет инцидент; ID назначается как в базе данных
+func (r *Repository) SaveIncident(i *domain.Incident) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i.ID == 0 {
+		i.ID = r.id()
+	}
+	r.incidents[i.ID] = *i
+	return nil
+}
+
+func (r *Repository) SaveChangePoint(cp *domain.ChangePoint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cp.ID = r.id()
+	r.changePoints = append(r.changePoints, *cp)
+	return nil
+}
+
+func (r *Repository) SaveSessionEvent(e *domain.SessionEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e.ID = r.id()
+	r.events = append(r.events, *e)
+	return nil
+}
+
+// вызывается под r.mu
+func (r *Repository) id() uint64 {
+	r.nextID++
+	return r.nextID
+}
+
+// Anomalies возвращает сохраненные аномалии в порядке сохранения
+func (r *Repository) Anomalies() []domain.Anomaly {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]domain.Anomaly(nil), r.anomalies...)
+}
+
+// Incident возвращает последнее сохраненное состояние инцидента
+func (r *Repository) Incident(id uint64) (domain.Incident, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.incidents[id]
+	return i, ok
+}
+
+// ChangePoints возвращает сохраненные точки изменения в порядке сохранения
+func (r *Repository) ChangePoints() []domain.ChangePoint {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]domain.ChangePoint(nil), r.changePoints...)
+}
+
+// SessionEvents возвращает сохраненные события потока в порядке сохранения
+func (r *Repository) SessionEvents() []domain.SessionEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]domain.SessionEvent(nil), r.events...)
+}
@@ -0,0 +1,101 @@
+// github.com/lonmouth/alien_wave/client/internal/application/changepoint.go
+package application
+
+import (
+	"log"
+	"time"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+)
+
+// сколько последних точек сессии хранится для оценки распределения после изменения
+const maxChangeRun = 1000
+
+// точка, учтенная обнаружением изменений
+type changeSample struct {
+	value float64
+	at    time.Time
+}
+
+// добавляет точку в кольцевой буфер последних точек сессии
+func (s *session) track(p changeSample) {
+	if len(s.samples) < maxChangeRun {
+		s.samples = append(s.samples, p)
+		return
+	}
+	s.samples[s.samplesNext] = p
+	s.samplesNext = (s.samplesNext + 1) % maxChangeRun
+}
+
+// возвращает не более n последних точек, старые первыми
+func (s *session) lastSamples(n uint) []changeSample {
+	size := len(s.samples)
+	n = min(n, uint(size))
+	out := make([]changeSample, 0, n)
+	for i := size - int(n); i < size; i++ {
+		out = append(out, s.samples[(s.samplesNext+i)%size])
+	}
+	return out
+}
+
+// проверяет, не изменилось ли распределение сессии. Вызывается для каждой точки после обучения
+// рядом с поточечной проверкой. Сначала точки сравниваются с базовой линией; если сессия после изменения
+// не переобучается, дальше они сравниваются с распределением после последнего изменения,
+// чтобы устойчивый сдвиг не сообщался повторно
+func (d *Detector) detectChange(s *session, point *transmitter.Transmission) {
+	ref := s.reference
+	if ref == nil {
+		ref = s.stats
+	}
+	if d.changes.Threshold <= 0 || ref.STD() == 0 {
+		return
+	}
+	s.track(changeSample{value: point.Frequency, at: pointTime(point)})
+	z := domain.ZScore(point.Frequency, ref.Mean(), ref.STD())
+	kind, run, stat, ok := s.cusum.Update(z, d.changes)
+	if !ok {
+		if s.reference != nil { // оценка по нескольким точкам после изменения уточняется новыми
+			s.reference.Update(point.Frequency)
+		}
+		return
+	}
+
+	after := s.lastSamples(run)
+	stats := domain.NewRunningStats()
+	for _, p := range after {
+		stats.Update(p.value)
+	}
+	cp := domain.ChangePoint{
+		SessionID:  s.id,
+		Kind:       kind,
+		StartedAt:  after[0].at,
+		DetectedAt: after[len(after)-1].at,
+		Points:     run,
+		Statistic:  stat,
+		BeforeMean: ref.Mean(),
+		BeforeSTD:  ref.STD(),
+		AfterMean:  stats.Mean(),
+		AfterSTD:   stats.STD(),
+	}
+	s.samples, s.samplesNext = s.samples[:0], 0
+	s.changes++
+	if d.changes.Retrain {
+		d.retrain(s)
+		cp.Retrained = true
+	} else if stats.STD() > 0 {
+		s.reference = stats
+	}
+
+	log.Printf("🔀 Change point | Session: %s | %s after %d points | μ %.2f→%.2f, σ %.2f→%.2f | retrain: %t",
+		cp.SessionID, cp.Kind, cp.Points, cp.BeforeMean, cp.AfterMean, cp.BeforeSTD, cp.AfterSTD, cp.Retrained)
+	notifier, _ := d.notifier.(domain.ChangePointNotifier)
+	d.later(func() {
+		if err := d.repo.SaveChangePoint(&cp); err != nil {
+			log.Printf("Failed to save change point: %v", err)
+		}
+		if notifier != nil {
+			notifier.NotifyChangePoint(cp)
+		}
+	})
+}
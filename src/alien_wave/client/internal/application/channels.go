@@ -0,0 +1,111 @@
+// github.com/lonmouth/alien_wave/client/internal/application/channels.go
+package application
+
+import (
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+)
+
+// значения точки по каналам: frequency первым, затем дополнительные каналы в порядке сессии.
+// Набор каналов сессии определяется первой точкой обучения с каналами; nil, если у точки он другой
+func (s *session) vector(point *transmitter.Transmission) []float64 {
+	if s.cov == nil {
+		if !s.trainingMode || len(point.Channels) == 0 {
+			return nil
+		}
+		for _, ch := range point.Channels {
+			s.channels = append(s.channels, ch.Name)
+		}
+		s.cov = domain.NewCovariance(1 + len(s.channels))
+	}
+	if len(point.Channels) != len(s.channels) {
+		return nil
+	}
+
+	x := make([]float64, 1, 1+len(s.channels))
+	x[0] = point.Frequency
+	for _, name := range s.channels {
+		found := false
+		for _, ch := range point.Channels {
+			if ch.Name == name {
+				x, found = append(x, ch.Value), true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return x
+}
+
+// учитывает каналы точки обучения в ковариационной матрице сессии
+func (s *session) trainChannels(point *transmitter.Transmission) {
+	if x := s.vector(point); x != nil {
+		s.cov.Update(x)
+	}
+}
+
+// проверяет дополнительные каналы точки по отдельности и, если включено, совместно по расстоянию Махаланобиса.
+// Каждый канал и совместная проверка проходят тот же путь, что и frequency: оценка и порог стратегии
+// с собственным подавлением дребезга. Абсолютные границы заданы для частоты, поэтому относятся
+// к совместной проверке, но не к отдельным каналам. Возвращает, найдена ли аномалия
+func (d *Detector) checkChannels(s *session, point *transmitter.Transmission) bool {
+	x := s.vector(point)
+	if x == nil {
+		return false
+	}
+	if len(s.channelDebounce) != len(s.channels) {
+		s.channelDebounce = make([]domain.Debouncer, len(s.channels))
+	}
+	checker := d.checkerFor(s)
+	found := false
+	for i, name := range s.channels {
+		stats := s.cov.Stats(i + 1)
+		if stats.STD() == 0 {
+			continue
+		}
+		if anomaly, _ := d.evaluate(&s.channelDebounce[i], checker, checker.Score(x[i+1], stats), domain.Limits{}, x[i+1]); !anomaly {
+			continue
+		}
+		anomaly := d.newAnomaly(point, x[i+1], stats, checker)
+		anomaly.Channel = name
+		d.record(s, anomaly, false)
+		found = true
+	}
+
+	if !d.joint {
+		return found
+	}
+	d2, ok := s.cov.Mahalanobis(x)
+	if !ok {
+		return found
+	}
+	z := domain.EquivalentZ(d2, len(x))
+	dir := domain.DirectionOf(point.Frequency, s.stats.Mean()) // сторона совместного отклонения - сторона frequency
+	anomaly, hardLimit := d.evaluate(&s.jointDebounce, checker, checker.JointScore(z, dir), checker.Limits, point.Frequency)
+	if !anomaly {
+		return found
+	}
+	joint := domain.Anomaly{
+		SessionID:        point.SessionId,
+		Channel:          domain.JointChannel,
+		Frequency:        point.Frequency,
+		Timestamp:        pointTime(point),
+		Seq:              point.Seq,
+		ExpectedMean:     s.stats.Mean(),
+		ExpectedSTD:      s.stats.STD(),
+		K:                checker.KFor(dir),
+		ConfigVersion:    d.version,
+		ZScore:           z,
+		Severity:         d.severity.Classify(z),
+		Algorithm:        domain.MahalanobisAlgorithm,
+		AlgorithmVersion: domain.MahalanobisAlgorithmVersion,
+	}
+	if hardLimit { // как и для frequency, выход за абсолютную границу всегда критичен
+		joint.Severity = domain.SeverityCritical
+		joint.Algorithm, joint.AlgorithmVersion = domain.HardLimitAlgorithm, domain.HardLimitAlgorithmVersion
+	}
+	d.record(s, joint, false)
+	return true
+}
@@ -4,38 +4,139 @@ import (
 	"context"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
 	"github.com/lonmouth/alien_wave/client/internal/domain"
-	transmitter "github.com/lonmouth/alien_wave/client/proto"
 )
 
+// сколько последних аномалий хранится в памяти для просмотра через admin API
+const recentAnomaliesSize = 100
+
 // структура, представляющая детектор аномалий
 type Detector struct {
-	repo         domain.AnomalyRepository // репозиторий для сохранения аномалий
-	stats        *domain.RunningStats     // объект для хранения статистических данных
-	checker      *domain.AnomalyChecker   // объект для проверки значений на аномальность
-	trainingMode bool                     // флаг, указывающий, находится ли детектор в режиме обучения
-	trainSize    uint                     // количество точек данных, необходимых для завершения обучения
-	logInterval  uint
-	currentUUID  string        // текущий идентификатор сессии
-	mu           sync.Mutex    // мьютекс используется для синхронизации доступа к общим ресурсам, таким как stats и currentUUID, чтобы избежать состояния гонки
-	shutdownCh   chan struct{} // канал, используемый для управления завершением работы детектора
-}
-
-func NewDetector(
-	repo domain.AnomalyRepository,
-	k float64,
-	trainSize uint,
-	logInterval uint,
-) *Detector {
-	return &Detector{
-		repo:        repo,
-		stats:       domain.NewRunningStats(),
-		checker:     domain.NewAnomalyChecker(k),
-		trainSize:   trainSize,
-		logInterval: logInterval,
-		shutdownCh:  make(chan struct{}), // создает канал shutdownCh для управления завершением работы
+	repo        domain.AnomalyRepository // репозиторий для сохранения аномалий
+	notifier    domain.AnomalyNotifier   // получатель уведомлений об аномалиях (может быть nil)
+	observer    Observer                 // получатель событий детектора (может быть nil)
+	metrics     Metrics                  // получатель счетчиков детектора (может быть nil)
+	checker     *domain.AnomalyChecker   // объект для проверки значений на аномальность (общие K, стороны и границы)
+	warmUp      domain.WarmUp            // критерии завершения обучения
+	training    domain.TrainingPolicy    // обработка отклонений во время обучения
+	robust      domain.Robust            // способ оценки базовой линии по окну обучения
+	logInterval uint                     // интервал логирования статистики (в точках данных)
+	incidentGap uint                     // сколько нормальных точек подряд завершают инцидент
+	severity    domain.SeverityScale     // пороги уровней важности аномалий
+	debounce    domain.Debounce          // подавление дребезга около порога
+	changes     domain.CUSUMConfig       // обнаружение изменений распределения
+	joint       bool                     // совместная проверка каналов по расстоянию Махаланобиса
+	version     uint64                   // версия примененной конфигурации
+	currentUUID string                   // текущий идентификатор сессии
+	sessions    map[string]*session      // состояние активных сессий
+	recent      []recentAnomaly          // кольцевой буфер последних аномалий
+	recentNext  int                      // позиция следующей записи в recent
+	paused      atomic.Bool              // приостановлен ли прием данных
+	dropped     atomic.Uint64            // количество точек, пропущенных во время паузы
+	mu          sync.Mutex               // мьютекс используется для синхронизации доступа к общим ресурсам, таким как sessions и currentUUID, чтобы избежать состояния гонки
+	writes      []func()                 // записи в репозиторий и уведомления, накопленные под mu; выполняются после ее снятия
+	writeMu     sync.Mutex               // выполняет накопленные записи разных вызовов по одному и в порядке накопления
+	shutdownCh  chan struct{}            // канал, используемый для управления завершением работы детектора
+}
+
+// аномалия в буфере последних. ID инцидента назначается при записи в репозиторий после снятия блокировки,
+// поэтому читается из общей с сессией ссылки
+type recentAnomaly struct {
+	domain.Anomaly
+	incident *atomic.Uint64
+}
+
+// Observer получает события детектора, например для вычисления правил алертинга.
+// Методы вызываются под блокировкой детектора и не должны обращаться к нему
+type Observer interface {
+	ObservePoint(sessionID string, at time.Time) // получена точка данных
+	ObserveAnomaly(a domain.Anomaly)             // найдена аномалия
+	SessionEnded(sessionID string)               // сессия больше не активна
+}
+
+// параметры детектора, которые можно менять без перезапуска
+type Settings struct {
+	Checker     domain.AnomalyChecker // коэффициенты, проверяемые стороны и абсолютные границы
+	WarmUp      domain.WarmUp         // критерии завершения обучения
+	Training    domain.TrainingPolicy // обработка отклонений во время обучения
+	Robust      domain.Robust         // способ оценки базовой линии по окну обучения
+	LogInterval uint                  // интервал логирования статистики
+	IncidentGap uint                  // сколько нормальных точек подряд завершают инцидент
+	Severity    domain.SeverityScale  // пороги уровней важности аномалий
+	Debounce    domain.Debounce       // гистерезис и подтверждение N из M точек
+	ChangePoint domain.CUSUMConfig    // обнаружение изменений распределения
+	Joint       bool                  // совместная проверка каналов по расстоянию Махаланобиса
+	Version     uint64                // версия конфигурации, записывается в новые аномалии
+}
+
+// создает детектор; параметры можно менять через Apply
+func NewDetector(repo domain.AnomalyRepository, s Settings) *Detector {
+	d := &Detector{
+		repo:       repo,
+		sessions:   make(map[string]*session),
+		recent:     make([]recentAnomaly, 0, recentAnomaliesSize),
+		shutdownCh: make(chan struct{}), // создает канал shutdownCh для управления завершением работы
+	}
+	d.Apply(s)
+	return d
+}
+
+// атомарно применяет новые параметры: точки, обрабатываемые после возврата из метода, используют уже новые значения
+func (d *Detector) Apply(s Settings) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	checker := s.Checker
+	d.checker = &checker // статистика сессий и режим обучения сохраняются
+	d.warmUp = s.WarmUp
+	d.training = s.Training
+	d.robust = s.Robust
+	d.logInterval = s.LogInterval
+	d.incidentGap = s.IncidentGap
+	d.severity = s.Severity
+	d.debounce = s.Debounce
+	d.changes = s.ChangePoint
+	d.joint = s.Joint
+	d.version = s.Version
+}
+
+// задает получателя уведомлений о найденных аномалиях
+func (d *Detector) SetNotifier(n domain.AnomalyNotifier) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.notifier = n
+}
+
+// задает получателя событий детектора
+func (d *Detector) SetObserver(o Observer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.observer = o
+}
+
+// возвращает действующие параметры детектора
+func (d *Detector) Settings() Settings {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return Settings{
+		Checker:     *d.checker,
+		WarmUp:      d.warmUp,
+		Training:    d.training,
+		Robust:      d.robust,
+		LogInterval: d.logInterval,
+		IncidentGap: d.incidentGap,
+		Severity:    d.severity,
+		Debounce:    d.debounce,
+		ChangePoint: d.changes,
+		Joint:       d.joint,
+		Version:     d.version,
 	}
 }
 
@@ -47,69 +148,176 @@ func (d *Detector) ShutdownChannel() <-chan struct{} {
 // метод Shutdown позволяет корректно завершать работу детектора, что важно для освобождения ресурсов и завершения всех операций
 func (d *Detector) Shutdown(ctx context.Context) error {
 	select {
-	case <-d.shutdownCh:  // если канал shutdownCh закрыт
-		return nil          // значит завершение работы уже было инициировано
-	case <-ctx.Done():    // если контекст ctx был отменен
-		return ctx.Err()    // метод возвращает ошибку, связанную с отменой контекста (ctx.Err())
+	case <-d.shutdownCh: // если канал shutdownCh закрыт
+		return nil // значит завершение работы уже было инициировано
+	case <-ctx.Done(): // если контекст ctx был отменен
+		return ctx.Err() // метод возвращает ошибку, связанную с отменой контекста (ctx.Err())
 	default:
 		close(d.shutdownCh) // метод закрывает канал shutdownCh, сигнализируя о необходимости завершения работы
+		d.mu.Lock()
+		for _, s := range d.sessions { // незавершенные эпизоды закрываются, чтобы не остаться открытыми в базе
+			d.closeIncident(s)
+		}
+		d.unlock()
 		return nil
 	}
 }
 
-func (d *Detector) saveAnomaly(point *transmitter.Transmission) {
-	anomaly := domain.Anomaly{ // создает объект Anomaly с данными из точки данных и текущей статистики
-		SessionID:    point.SessionId,
-		Frequency:    point.Frequency,
-		Timestamp:    time.Unix(point.TimestampUtc, 0),
-		ExpectedMean: d.stats.Mean(),
-		ExpectedSTD:  d.stats.STD(),
-		K:            d.checker.K,
+// hardLimit - аномалия найдена только по абсолютным границам, отклонение от среднего в норме;
+// provisional - аномалия найдена во время обучения, о ней не уведомляются получатели
+func (d *Detector) saveAnomaly(s *session, point *transmitter.Transmission, hardLimit, provisional bool) {
+	anomaly := d.newAnomaly(point, point.Frequency, s.stats, d.checkerFor(s))
+	anomaly.Provisional = provisional
+	if s.estimator != "" && s.estimator != domain.EstimatorMean {
+		anomaly.Algorithm += "+" + string(s.estimator) // базовая линия оценена устойчиво
+	}
+	if hardLimit { // выход за абсолютную границу всегда критичен
+		anomaly.Severity = domain.SeverityCritical
+		anomaly.Algorithm, anomaly.AlgorithmVersion = domain.HardLimitAlgorithm, domain.HardLimitAlgorithmVersion
 	}
+	d.record(s, anomaly, provisional)
+}
 
-	if err := d.repo.Save(anomaly); err != nil {
-		log.Printf("Failed to save anomaly: %v", err)
+// создает аномалию значения value с данными из точки и статистики канала
+func (d *Detector) newAnomaly(point *transmitter.Transmission, value float64, stats *domain.RunningStats, checker *domain.AnomalyChecker) domain.Anomaly {
+	z := domain.ZScore(value, stats.Mean(), stats.STD())
+	dir := domain.DirectionOf(value, stats.Mean())
+	return domain.Anomaly{
+		SessionID:        point.SessionId,
+		Frequency:        value,
+		Timestamp:        pointTime(point),
+		Seq:              point.Seq,
+		ExpectedMean:     stats.Mean(),
+		ExpectedSTD:      stats.STD(),
+		K:                checker.KFor(dir),
+		ConfigVersion:    d.version,
+		ZScore:           z,
+		Direction:        dir,
+		Severity:         d.severity.Classify(z),
+		Algorithm:        domain.ZScoreAlgorithm,
+		AlgorithmVersion: domain.ZScoreAlgorithmVersion,
 	}
 }
 
-func (d *Detector) reset() {
-	d.stats = domain.NewRunningStats()
-	d.trainingMode = true
+// связывает аномалию с инцидентом, сохраняет ее и уведомляет получателей.
+// Сохранение и уведомление выполняются после снятия блокировки, когда ID инцидента уже известен
+func (d *Detector) record(s *session, anomaly domain.Anomaly, provisional bool) {
+	d.trackIncident(s, anomaly)
+	s.anomalies++
+	incident := s.incidentID
+	d.remember(anomaly, incident)
+
+	notifier := d.notifier
+	if provisional {
+		notifier = nil
+	}
+	d.later(func() {
+		anomaly.IncidentID = incident.Load() // 0, если инцидент еще ни разу не удалось сохранить
+		if err := d.repo.Save(anomaly); err != nil {
+			log.Printf("Failed to save anomaly: %v", err)
+		}
+		if notifier != nil { // уведомление отправляется даже если сохранить аномалию не удалось
+			notifier.Notify(anomaly)
+		}
+	})
+	if !provisional && d.observer != nil {
+		d.observer.ObserveAnomaly(anomaly)
+	}
 }
 
-func (d *Detector) Process(point *transmitter.Transmission) { // метод для обработки точки данных (обрабатывает данные в реальном времени)
-	log.Printf("📥 Received data | Session: %s | Freq: %.2f", 
-        point.SessionId, point.Frequency)
+// добавляет аномалию в кольцевой буфер последних аномалий
+func (d *Detector) remember(a domain.Anomaly, incident *atomic.Uint64) {
+	r := recentAnomaly{Anomaly: a, incident: incident}
+	if len(d.recent) < cap(d.recent) {
+		d.recent = append(d.recent, r)
+		return
+	}
+	d.recent[d.recentNext] = r
+	d.recentNext = (d.recentNext + 1) % len(d.recent)
+}
 
-	if d.currentUUID != point.SessionId {
-		d.reset()                       // сбрасывает статистику
-		d.currentUUID = point.SessionId // устанавливает новый currentUUID
+// откладывает запись в репозиторий до снятия блокировки детектора: медленная база
+// не должна задерживать обработку точек и чтение состояния. Вызывается под d.mu
+func (d *Detector) later(write func()) {
+	d.writes = append(d.writes, write)
+}
+
+// снимает блокировку детектора и выполняет накопленные под ней записи. writeMu берется до снятия mu,
+// поэтому записи следующего вызова выполняются только после записей этого
+func (d *Detector) unlock() {
+	writes := d.writes
+	d.writes = nil
+	d.writeMu.Lock()
+	defer d.writeMu.Unlock()
+	d.mu.Unlock()
+
+	for _, write := range writes {
+		write()
 	}
+}
 
-	if d.trainingMode {
-		d.stats.Update(point.Frequency)     // обновляет статистику
-		if d.stats.Count() >= d.trainSize { // проверяет, завершено ли обучение
-			d.trainingMode = false
-			log.Printf("Training completed. μ=%.2f, σ=%.2f", d.stats.Mean(), d.stats.STD())
-		}
+// оценивает точку стратегией сессии с подавлением дребезга около порога.
+// Выход за абсолютные границы делает точку аномальной сразу, без подтверждения:
+// anomaly сообщает итог, hardLimit - что аномалия найдена только по границам
+func (d *Detector) isAnomaly(s *session, value float64) (anomaly, hardLimit bool) {
+	checker := d.checkerFor(s)
+	return d.evaluate(&s.debounce, checker, checker.Score(value, s.stats), checker.Limits, value)
+}
+
+// общий путь всех проверок (frequency, отдельные каналы, совместная): оценка score стратегии strategy
+// сравнивается с ее порогом с подавлением дребезга последовательности deb, выход value за limits
+// делает точку аномальной без подтверждения
+func (d *Detector) evaluate(deb *domain.Debouncer, strategy domain.Strategy, score float64, limits domain.Limits, value float64) (anomaly, hardLimit bool) {
+	soft := deb.Observe(score, strategy.Threshold(), d.debounce)
+	hard := limits.Violated(value)
+	return soft || hard, hard && !soft
+}
+
+// возвращает checker сессии: с собственным K, если он задан для сессии, иначе общий
+func (d *Detector) checkerFor(s *session) *domain.AnomalyChecker {
+	if s.k != 0 {
+		return d.checker.WithK(s.k)
 	}
+	return d.checker
+}
 
-	if d.checker.IsAnomaly(point.Frequency, d.stats) { // является ли точка данных аномальной
-		d.saveAnomaly(point)
+func (d *Detector) Process(point *transmitter.Transmission) { // метод для обработки точки данных (обрабатывает данные в реальном времени)
+	if d.paused.Load() { // во время паузы точки не попадают в статистику
+		d.dropped.Add(1)
+		return
 	}
-// 	if d.checker.IsAnomaly(point.Frequency, d.stats) || d.stats.Count()%50 == 0 {
-// 		anomaly := domain.Anomaly{
-// 				SessionID:    "TEST-ANOMALY",
-// 				Frequency:    100.0,
-// 				Timestamp:    time.Now(),
-// 				ExpectedMean: d.stats.Mean(),
-// 				ExpectedSTD:  d.stats.STD(),
-// 				K:            d.checker.K,
-// 		}
-// 		d.repo.Save(anomaly)
-// }
 
-	if d.stats.Count()%10 == 0 { // логирует статистику каждые 10 точек данных
-		log.Printf("Processed: %d, μ=%.2f, σ=%.2f", d.stats.Count(), d.stats.Mean(), d.stats.STD())
+	log.Printf("📥 Received data | Session: %s | Freq: %.2f",
+		point.SessionId, point.Frequency)
+
+	d.mu.Lock()
+	defer d.unlock()
+
+	s, ok := d.sessions[point.SessionId]
+	if !ok || d.currentUUID != point.SessionId { // без SessionStart новая сессия определяется по смене идентификатора
+		s = d.startSession(point.SessionId)
+	}
+	if !d.checkSequence(s, point) {
+		return
+	}
+	s.lastSeen = time.Now()
+	if d.observer != nil {
+		d.observer.ObservePoint(point.SessionId, s.lastSeen)
+	}
+
+	if s.trainingMode {
+		d.train(s, point)
+	} else {
+		anomaly, hardLimit := d.isAnomaly(s, point.Frequency) // является ли точка данных аномальной
+		if anomaly {
+			d.saveAnomaly(s, point, hardLimit, false)
+		}
+		if channels := d.checkChannels(s, point); !anomaly && !channels { // инцидент продолжается, пока аномален хотя бы один канал
+			d.quietPoint(s)
+		}
+		d.detectChange(s, point) // изменение распределения ищется рядом с поточечной проверкой
+	}
+	if s.stats.Count()%d.logInterval == 0 { // логирует статистику каждые logInterval точек данных
+		log.Printf("Processed: %d, μ=%.2f, σ=%.2f", s.stats.Count(), s.stats.Mean(), s.stats.STD())
 	}
 }
@@ -0,0 +1,76 @@
+// github.com/lonmouth/alien_wave/client/internal/application/events.go
+package application
+
+import (
+	"log"
+	"time"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+)
+
+// Metrics получает события детектора для экспорта счетчиков, например в Prometheus.
+// Методы вызываются под блокировкой детектора и не должны обращаться к нему
+type Metrics interface {
+	SessionEvent(e domain.SessionEvent) // обнаружено событие потока сессии
+}
+
+// задает получателя счетчиков детектора
+func (d *Detector) SetMetrics(m Metrics) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.metrics = m
+}
+
+// время точки данных: с наносекундами, если сервер их передает, иначе целые секунды timestamp_utc
+func pointTime(point *transmitter.Transmission) time.Time {
+	if point.Timestamp != nil {
+		return point.Timestamp.AsTime()
+	}
+	return time.Unix(point.TimestampUtc, 0)
+}
+
+// проверяет номер точки. Возвращает false для повтора уже полученной точки: она не должна
+// второй раз попасть в статистику
+func (d *Detector) checkSequence(s *session, point *transmitter.Transmission) bool {
+	kind, expected, count := s.sequence.Observe(point.Seq)
+	if kind == "" {
+		return true
+	}
+	d.sessionEvent(s, domain.SessionEvent{
+		SessionID: s.id,
+		Kind:      kind,
+		At:        pointTime(point),
+		Seq:       point.Seq,
+		Expected:  expected,
+		Count:     count,
+	})
+	return kind != domain.EventDuplicate
+}
+
+// учитывает событие сессии в счетчиках, сохраняет его и передает в метрики
+func (d *Detector) sessionEvent(s *session, e domain.SessionEvent) {
+	if s.events == nil {
+		s.events = make(map[domain.SessionEventKind]uint64)
+	}
+	s.events[e.Kind]++
+	s.missing += e.Count
+
+	saved := e
+	d.later(func() {
+		if err := d.repo.SaveSessionEvent(&saved); err != nil {
+			log.Printf("Failed to save session event: %v", err)
+		}
+	})
+	switch e.Kind {
+	case domain.EventGap, domain.EventDuplicate, domain.EventReorder:
+		log.Printf("⚠️ Session event | Session: %s | %s | seq=%d, expected=%d, missing=%d",
+			e.SessionID, e.Kind, e.Seq, e.Expected, e.Count)
+	default:
+		log.Printf("⚠️ Session event | Session: %s | %s | seq=%d | %s", e.SessionID, e.Kind, e.Seq, e.Detail)
+	}
+	if d.metrics != nil {
+		d.metrics.SessionEvent(e)
+	}
+}
@@ -0,0 +1,64 @@
+// github.com/lonmouth/alien_wave/client/internal/application/incident.go
+package application
+
+import (
+	"log"
+	"sync/atomic"
+
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+)
+
+// добавляет аномалию в открытый инцидент сессии (или открывает новый); ID инцидента
+// появляется в s.incidentID после его первой записи в репозиторий
+func (d *Detector) trackIncident(s *session, a domain.Anomaly) {
+	if s.incident == nil {
+		s.incident, s.incidentID = domain.NewIncident(a), new(atomic.Uint64)
+		log.Printf("Incident opened | Session: %s | Direction: %s", s.id, s.incident.Direction)
+	} else {
+		s.incident.Add(a)
+	}
+	s.quiet = 0
+	d.saveIncident(*s.incident, s.incidentID)
+}
+
+// откладывает запись снимка инцидента; назначенный репозиторием ID сохраняется в id
+// и используется следующими записями того же инцидента. О завершенном инциденте уведомляются получатели
+func (d *Detector) saveIncident(inc domain.Incident, id *atomic.Uint64) {
+	notifier, _ := d.notifier.(domain.IncidentNotifier)
+	d.later(func() {
+		inc.ID = id.Load()
+		if err := d.repo.SaveIncident(&inc); err != nil {
+			log.Printf("Failed to save incident: %v", err)
+		}
+		id.Store(inc.ID)
+		if inc.Closed && notifier != nil {
+			notifier.NotifyIncidentClosed(inc)
+		}
+	})
+}
+
+// учитывает нормальную точку: после incidentGap нормальных точек подряд инцидент завершается
+func (d *Detector) quietPoint(s *session) {
+	if s.incident == nil {
+		return
+	}
+	s.quiet++
+	if s.quiet >= d.incidentGap {
+		d.closeIncident(s)
+	}
+}
+
+// завершает открытый инцидент сессии, если он есть
+func (d *Detector) closeIncident(s *session) {
+	inc := s.incident
+	if inc == nil {
+		return
+	}
+	id := s.incidentID
+	s.incident, s.incidentID, s.quiet = nil, nil, 0
+
+	inc.Closed = true
+	d.saveIncident(*inc, id)
+	log.Printf("Incident closed | Session: %s | Points: %d | Peak: %.2f (%.2fσ) | Direction: %s | Duration: %v",
+		inc.SessionID, inc.Points, inc.PeakFrequency, inc.PeakDeviation, inc.Direction, inc.EndedAt.Sub(inc.StartedAt))
+}
@@ -0,0 +1,142 @@
+// github.com/lonmouth/alien_wave/client/internal/application/lifecycle.go
+package application
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+)
+
+// истинные параметры генератора сессии, если сервер разрешает их раскрывать
+type Generator struct {
+	Model string  `json:"model"`
+	Mean  float64 `json:"mean"`
+	STD   float64 `json:"std"`
+}
+
+// StartSession начинает сессию по сообщению сервера: предыдущая сессия завершается,
+// статистика новой накапливается с нуля. Продолженная после переподключения сессия сохраняет статистику
+func (d *Detector) StartSession(start *transmitter.SessionStart) {
+	d.mu.Lock()
+	defer d.unlock()
+
+	if s, ok := d.sessions[start.SessionId]; ok && start.Resumed {
+		d.currentUUID = s.id
+		d.sessionEvent(s, domain.SessionEvent{
+			SessionID: s.id,
+			Kind:      domain.EventSessionResume,
+			At:        time.Now(),
+			Seq:       s.sequence.Last(),
+		})
+		return
+	}
+	s := d.startSession(start.SessionId)
+	e := domain.SessionEvent{
+		SessionID: s.id,
+		Kind:      domain.EventSessionStart,
+		At:        start.StartedAt.AsTime(),
+	}
+	if p := start.Parameters; p != nil {
+		s.generator = &Generator{Model: p.Model, Mean: p.Mean, STD: p.Std}
+		e.Detail = fmt.Sprintf("model=%s μ=%.4f σ=%.4f", p.Model, p.Mean, p.Std)
+	}
+	d.sessionEvent(s, e)
+}
+
+// Heartbeat отмечает, что сервер жив: отсутствие точек при регулярных heartbeat - тишина источника, а не обрыв связи
+func (d *Detector) Heartbeat(hb *transmitter.Heartbeat) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if s, ok := d.sessions[hb.SessionId]; ok {
+		s.heartbeat = time.Now()
+	}
+}
+
+// EndSession завершает сессию по сообщению сервера и сохраняет причину. Точки, отправленные сервером
+// после последней полученной, учитываются как пропуск
+func (d *Detector) EndSession(end *transmitter.SessionEnd) {
+	d.mu.Lock()
+	defer d.unlock()
+
+	s, ok := d.sessions[end.SessionId]
+	if !ok {
+		log.Printf("Session end for unknown session %s: %s", end.SessionId, endReason(end))
+		return
+	}
+	at := end.EndedAt.AsTime()
+	if last := s.sequence.Last(); end.LastSeq > last {
+		d.sessionEvent(s, domain.SessionEvent{
+			SessionID: s.id,
+			Kind:      domain.EventGap,
+			At:        at,
+			Seq:       end.LastSeq,
+			Expected:  last + 1,
+			Count:     end.LastSeq - last,
+		})
+	}
+	d.sessionEvent(s, domain.SessionEvent{
+		SessionID: s.id,
+		Kind:      domain.EventSessionEnd,
+		At:        at,
+		Seq:       end.LastSeq,
+		Detail:    endReason(end),
+	})
+	d.endSession(s.id)
+}
+
+// LinkLost отмечает, что от сервера дольше silence нет ни точек, ни heartbeat.
+// Сессия остается активной: после восстановления соединения она может продолжиться
+func (d *Detector) LinkLost(silence time.Duration) {
+	d.mu.Lock()
+	defer d.unlock()
+
+	s, ok := d.sessions[d.currentUUID]
+	if !ok {
+		log.Printf("Link lost: no messages for %v", silence.Round(time.Millisecond))
+		return
+	}
+	d.sessionEvent(s, domain.SessionEvent{
+		SessionID: s.id,
+		Kind:      domain.EventLinkLost,
+		At:        time.Now(),
+		Seq:       s.sequence.Last(),
+		Detail:    fmt.Sprintf("no messages for %v", silence.Round(time.Millisecond)),
+	})
+}
+
+// завершает текущую сессию и делает текущей новую сессию id
+func (d *Detector) startSession(id string) *session {
+	d.endSession(d.currentUUID) // предыдущая сессия больше не активна
+	s := newSession(id)         // начинает новую статистику
+	d.sessions[id] = s
+	d.currentUUID = id
+	return s
+}
+
+// закрывает инцидент сессии и удаляет ее из активных
+func (d *Detector) endSession(id string) {
+	if s, ok := d.sessions[id]; ok {
+		d.closeIncident(s)
+		if d.observer != nil {
+			d.observer.SessionEnded(id)
+		}
+	}
+	delete(d.sessions, id)
+	if d.currentUUID == id {
+		d.currentUUID = ""
+	}
+}
+
+// причина завершения сессии в виде "server_shutdown: server is shutting down"
+func endReason(end *transmitter.SessionEnd) string {
+	reason := strings.ToLower(strings.TrimPrefix(end.Reason.String(), "REASON_"))
+	if end.Detail != "" {
+		reason += ": " + end.Detail
+	}
+	return reason
+}
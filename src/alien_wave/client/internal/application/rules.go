@@ -0,0 +1,331 @@
+// github.com/lonmouth/alien_wave/client/internal/application/rules.go
+package application
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// виды условий правил
+const (
+	RuleCount  = "count"  // не меньше Threshold аномалий сессии за Window
+	RuleZScore = "zscore" // аномалия с |z| > Threshold за последние Window
+	RuleAbsent = "absent" // от сессии нет данных дольше Window
+)
+
+// декларативное правило алертинга поверх потока аномалий
+type Rule struct {
+	Name      string        `yaml:"name"`
+	Kind      string        `yaml:"kind"`      // count, zscore или absent
+	Threshold float64       `yaml:"threshold"` // порог для count и zscore
+	Window    time.Duration `yaml:"window"`    // окно наблюдения
+	Severity  string        `yaml:"severity"`  // важность, передается в уведомление
+	For       time.Duration `yaml:"for"`       // сколько условие должно выполняться до срабатывания
+	Resolve   time.Duration `yaml:"resolve"`   // сколько условие должно не выполняться до разрешения
+
+	MinSeverity domain.Severity `yaml:"min_severity"` // учитывать только аномалии не ниже уровня (count и zscore)
+}
+
+// читает правила из YAML-файла вида `rules: [...]`
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rules: %w", err)
+	}
+	var file struct {
+		Rules []Rule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse rules %s: %w", path, err)
+	}
+	if err := ValidateRules(file.Rules); err != nil {
+		return nil, fmt.Errorf("rules %s: %w", path, err)
+	}
+	return file.Rules, nil
+}
+
+// проверяет правила и возвращает сразу все найденные проблемы
+func ValidateRules(rules []Rule) error {
+	var errs []error
+	names := make(map[string]bool)
+	for i, r := range rules {
+		if r.Name == "" {
+			errs = append(errs, fmt.Errorf("rule #%d: name is required", i+1))
+		} else if names[r.Name] {
+			errs = append(errs, fmt.Errorf("rule %q: duplicate name", r.Name))
+		}
+		names[r.Name] = true
+
+		switch r.Kind {
+		case RuleCount:
+			if r.Threshold < 1 || r.Window <= 0 {
+				errs = append(errs, fmt.Errorf("rule %q: count needs threshold >= 1 and a positive window", r.Name))
+			}
+		case RuleZScore:
+			if r.Threshold <= 0 || r.Window <= 0 {
+				errs = append(errs, fmt.Errorf("rule %q: zscore needs a positive threshold and a positive window", r.Name))
+			}
+		case RuleAbsent:
+			if r.Window <= 0 {
+				errs = append(errs, fmt.Errorf("rule %q: absent needs a positive window", r.Name))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("rule %q: unknown kind %q", r.Name, r.Kind))
+		}
+		if r.For < 0 || r.Resolve < 0 || r.Window < 0 {
+			errs = append(errs, fmt.Errorf("rule %q: durations must not be negative", r.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// состояние правила для одной сессии
+type ruleState struct {
+	active   bool      // условие выполняется
+	since    time.Time // когда условие последний раз изменилось
+	firing   bool      // отправлено уведомление firing
+	startsAt time.Time // когда правило сработало
+	value    float64   // значение условия при последней проверке
+}
+
+// аномалия, учтенная правилами
+type anomalyEvent struct {
+	at       time.Time       // время получения
+	z        float64         // модуль z-оценки
+	severity domain.Severity // уровень важности
+}
+
+// наблюдения по одной сессии
+type sessionEvents struct {
+	anomalies []anomalyEvent // аномалии в пределах максимального окна
+	lastPoint time.Time      // когда пришла последняя точка данных
+}
+
+// RuleEngine вычисляет правила по событиям детектора и уведомляет о срабатывании и разрешении
+type RuleEngine struct {
+	mu       sync.Mutex
+	rules    []Rule
+	sessions map[string]*sessionEvents
+	states   map[string]map[string]*ruleState // правило -> сессия -> состояние
+	notifier domain.AlertNotifier
+	now      func() time.Time
+}
+
+func NewRuleEngine(rules []Rule, notifier domain.AlertNotifier) *RuleEngine {
+	e := &RuleEngine{
+		sessions: make(map[string]*sessionEvents),
+		states:   make(map[string]map[string]*ruleState),
+		notifier: notifier,
+		now:      time.Now,
+	}
+	e.SetRules(rules)
+	return e
+}
+
+// заменяет набор правил; состояние сохраняется у правил, описание которых не изменилось
+func (e *RuleEngine) SetRules(rules []Rule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	old := make(map[string]Rule, len(e.rules))
+	for _, r := range e.rules {
+		old[r.Name] = r
+	}
+	states := make(map[string]map[string]*ruleState, len(rules))
+	for _, r := range rules {
+		if prev, ok := old[r.Name]; ok && prev == r {
+			states[r.Name] = e.states[r.Name]
+		} else {
+			states[r.Name] = make(map[string]*ruleState)
+		}
+	}
+	e.rules = append([]Rule(nil), rules...)
+	e.states = states
+}
+
+// регистрирует точку данных сессии
+func (e *RuleEngine) ObservePoint(sessionID string, _ time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.session(sessionID).lastPoint = e.now()
+}
+
+// регистрирует аномалию и сразу вычисляет правила сессии
+func (e *RuleEngine) ObserveAnomaly(a domain.Anomaly) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	s := e.session(a.SessionID)
+	s.anomalies = append(s.anomalies, anomalyEvent{at: now, z: domain.Deviation(a), severity: a.Severity})
+	e.evaluate(a.SessionID, s, now)
+}
+
+// завершает наблюдение за сессией. Правила сначала вычисляются последний раз, чтобы сработали
+// ожидающие условия (например, absent после долгой тишины перед завершением), затем сработавшие разрешаются
+func (e *RuleEngine) SessionEnded(sessionID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	if s, ok := e.sessions[sessionID]; ok {
+		e.evaluate(sessionID, s, now)
+	}
+	for _, r := range e.rules {
+		if st, ok := e.states[r.Name][sessionID]; ok && st.firing {
+			e.notify(r, sessionID, st, domain.AlertResolved, now)
+		}
+		delete(e.states[r.Name], sessionID)
+	}
+	delete(e.sessions, sessionID)
+}
+
+// вычисляет правила всех сессий; вызывается периодически для условий, зависящих от времени
+func (e *RuleEngine) Tick() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	for id, s := range e.sessions {
+		e.evaluate(id, s, now)
+	}
+}
+
+// периодически вызывает Tick до закрытия stop
+func (e *RuleEngine) Run(stop <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			e.Tick()
+		}
+	}
+}
+
+func (e *RuleEngine) session(id string) *sessionEvents {
+	s, ok := e.sessions[id]
+	if !ok {
+		s = &sessionEvents{lastPoint: e.now()}
+		e.sessions[id] = s
+	}
+	return s
+}
+
+func (e *RuleEngine) evaluate(sessionID string, s *sessionEvents, now time.Time) {
+	var maxWindow time.Duration
+	for _, r := range e.rules {
+		maxWindow = max(maxWindow, r.Window)
+		active, value := condition(r, s, now)
+		e.transition(r, sessionID, active, value, now)
+	}
+
+	// аномалии старше самого длинного окна больше не нужны
+	cut := 0
+	for cut < len(s.anomalies) && now.Sub(s.anomalies[cut].at) > maxWindow {
+		cut++
+	}
+	s.anomalies = s.anomalies[cut:]
+}
+
+// вычисляет условие правила и значение, на котором оно проверялось
+func condition(r Rule, s *sessionEvents, now time.Time) (bool, float64) {
+	switch r.Kind {
+	case RuleCount:
+		n := 0
+		for _, ev := range s.anomalies {
+			if now.Sub(ev.at) <= r.Window && ev.severity >= r.MinSeverity {
+				n++
+			}
+		}
+		return float64(n) >= r.Threshold, float64(n)
+	case RuleZScore:
+		var z float64 // наибольшая |z| подходящих аномалий за окно
+		for _, ev := range s.anomalies {
+			if now.Sub(ev.at) <= r.Window && ev.severity >= r.MinSeverity {
+				z = max(z, ev.z)
+			}
+		}
+		return z > r.Threshold, z
+	case RuleAbsent:
+		silence := now.Sub(s.lastPoint)
+		return silence > r.Window, silence.Seconds()
+	}
+	return false, 0
+}
+
+// конечный автомат правила: pending (For) -> firing -> resolving (Resolve) -> resolved
+func (e *RuleEngine) transition(r Rule, sessionID string, active bool, value float64, now time.Time) {
+	st, ok := e.states[r.Name][sessionID]
+	if !ok {
+		if !active {
+			return
+		}
+		st = &ruleState{}
+		e.states[r.Name][sessionID] = st
+	}
+
+	if active != st.active {
+		st.active, st.since = active, now
+	}
+	if active {
+		st.value = value
+	}
+
+	switch {
+	case active && !st.firing && now.Sub(st.since) >= r.For:
+		st.firing, st.startsAt = true, now
+		e.notify(r, sessionID, st, domain.AlertFiring, now)
+	case !active && st.firing && now.Sub(st.since) >= r.Resolve:
+		e.notify(r, sessionID, st, domain.AlertResolved, now)
+		delete(e.states[r.Name], sessionID)
+	case !active && !st.firing:
+		delete(e.states[r.Name], sessionID) // условие пропало, не дождавшись For
+	}
+}
+
+func (e *RuleEngine) notify(r Rule, sessionID string, st *ruleState, state domain.AlertState, now time.Time) {
+	severity := r.Severity
+	if severity == "" {
+		severity = "warning"
+	}
+	alert := domain.Alert{
+		Rule:      r.Name,
+		Severity:  severity,
+		State:     state,
+		SessionID: sessionID,
+		StartsAt:  st.startsAt,
+		Value:     st.value,
+		Summary:   summary(r, st.value),
+	}
+	if state == domain.AlertResolved {
+		alert.EndsAt = now
+	}
+
+	log.Printf("🚨 Alert %s | Rule: %s | Severity: %s | Session: %s | %s", state, r.Name, severity, sessionID, alert.Summary)
+	if e.notifier != nil {
+		e.notifier.NotifyAlert(alert)
+	}
+}
+
+func summary(r Rule, value float64) string {
+	switch r.Kind {
+	case RuleCount:
+		return fmt.Sprintf("%.0f anomalies in %v (threshold %.0f)", value, r.Window, r.Threshold)
+	case RuleZScore:
+		return fmt.Sprintf("anomaly with |z|=%.2f (threshold %.2f)", value, r.Threshold)
+	case RuleAbsent:
+		return fmt.Sprintf("no data for %.0fs (threshold %v)", value, r.Window)
+	}
+	return ""
+}
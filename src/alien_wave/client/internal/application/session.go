@@ -0,0 +1,206 @@
+// github.com/lonmouth/alien_wave/client/internal/application/session.go
+package application
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+)
+
+// сколько последних значений обучения хранится для устойчивой оценки базовой линии
+const maxTrainingWindow = 10000
+
+// ошибка, возвращаемая при обращении к неизвестной сессии
+var ErrSessionNotFound = errors.New("session not found")
+
+// состояние обнаружения для одной сессии
+type session struct {
+	id              string
+	stats           *domain.RunningStats               // статистика сессии
+	k               float64                            // собственный K сессии в обе стороны (0 - используется общий)
+	trainingMode    bool                               // находится ли сессия в режиме обучения
+	anomalies       uint                               // количество найденных аномалий
+	lastSeen        time.Time                          // время получения последней точки
+	incident        *domain.Incident                   // открытый инцидент (nil - аномалий сейчас нет)
+	incidentID      *atomic.Uint64                     // ID открытого инцидента, назначается при его записи после снятия блокировки
+	quiet           uint                               // количество нормальных точек после последней аномалии
+	debounce        domain.Debouncer                   // подавление дребезга около порога
+	window          []float64                          // значения окна обучения для устойчивой оценки базовой линии
+	windowNext      int                                // позиция следующей записи в заполненном window
+	estimator       domain.Estimator                   // способ оценки базовой линии после обучения
+	cusum           domain.CUSUM                       // статистики обнаружения изменений распределения
+	reference       *domain.RunningStats               // распределение после последнего изменения без переобучения (nil - базовая линия)
+	samples         []changeSample                     // последние точки после обучения для оценки распределения после изменения
+	samplesNext     int                                // позиция следующей записи в заполненном samples
+	changes         uint                               // количество найденных изменений распределения
+	channels        []string                           // имена дополнительных каналов сессии
+	cov             *domain.Covariance                 // ковариация frequency и дополнительных каналов по точкам обучения
+	channelDebounce []domain.Debouncer                 // подавление дребезга каждого дополнительного канала
+	jointDebounce   domain.Debouncer                   // подавление дребезга совместной проверки
+	sequence        domain.SequenceTracker             // проверка номеров точек
+	events          map[domain.SessionEventKind]uint64 // количество событий потока по видам
+	missing         uint64                             // количество пропущенных точек
+	generator       *Generator                         // истинные параметры генератора (nil - сервер их не раскрыл)
+	heartbeat       time.Time                          // время получения последнего heartbeat
+}
+
+// добавляет значение в окно обучения; в заполненном окне заменяется самое старое значение
+func (s *session) remember(v float64) {
+	if len(s.window) < maxTrainingWindow {
+		s.window = append(s.window, v)
+		return
+	}
+	s.window[s.windowNext] = v
+	s.windowNext = (s.windowNext + 1) % maxTrainingWindow
+}
+
+func newSession(id string) *session {
+	return &session{
+		id:           id,
+		stats:        domain.NewRunningStats(),
+		trainingMode: true,
+	}
+}
+
+// снимок состояния сессии для просмотра извне
+type SessionInfo struct {
+	ID        string            `json:"id"`
+	Mean      float64           `json:"mean"`
+	STD       float64           `json:"std"`
+	Count     uint              `json:"count"`
+	Training  bool              `json:"training"`
+	K         float64           `json:"k"`
+	KOverride bool              `json:"k_override"` // задан ли для сессии собственный K
+	Anomalies uint              `json:"anomalies"`
+	Changes   uint              `json:"change_points"`            // количество найденных изменений распределения
+	Channels  []string          `json:"channels,omitempty"`       // дополнительные каналы сессии
+	Events    map[string]uint64 `json:"events,omitempty"`         // количество событий потока: gap, duplicate, reorder
+	Missing   uint64            `json:"missing_points,omitempty"` // количество пропущенных точек
+	Generator *Generator        `json:"generator,omitempty"`      // истинные параметры генератора, если сервер их раскрыл
+	Incident  uint64            `json:"incident,omitempty"`       // открытый инцидент сессии
+	Estimator string            `json:"estimator,omitempty"`      // способ оценки базовой линии после обучения
+	LastSeen  time.Time         `json:"last_seen"`
+	Heartbeat *time.Time        `json:"last_heartbeat,omitempty"` // heartbeat без точек означает тишину источника при живом соединении
+}
+
+// возвращает состояние всех активных сессий
+func (d *Detector) Sessions() []SessionInfo {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	infos := make([]SessionInfo, 0, len(d.sessions))
+	for _, s := range d.sessions {
+		info := SessionInfo{
+			ID:        s.id,
+			Mean:      s.stats.Mean(),
+			STD:       s.stats.STD(),
+			Count:     s.stats.Count(),
+			Training:  s.trainingMode,
+			K:         d.checkerFor(s).K,
+			KOverride: s.k != 0,
+			Anomalies: s.anomalies,
+			Changes:   s.changes,
+			Channels:  s.channels,
+			Missing:   s.missing,
+			Generator: s.generator,
+			LastSeen:  s.lastSeen,
+			Estimator: string(s.estimator),
+		}
+		if s.incidentID != nil {
+			info.Incident = s.incidentID.Load()
+		}
+		if !s.heartbeat.IsZero() {
+			heartbeat := s.heartbeat // снимок: поле сессии меняется под d.mu после возврата
+			info.Heartbeat = &heartbeat
+		}
+		if len(s.events) > 0 {
+			info.Events = make(map[string]uint64, len(s.events))
+			for kind, n := range s.events {
+				info.Events[string(kind)] = n
+			}
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].LastSeen.After(infos[j].LastSeen) })
+	return infos
+}
+
+// возвращает не более limit последних аномалий, новые первыми
+func (d *Detector) RecentAnomalies(limit int) []domain.Anomaly {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	n := len(d.recent)
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+	out := make([]domain.Anomaly, 0, limit)
+	for i := 0; i < limit; i++ {
+		// recentNext указывает на самую старую запись заполненного буфера
+		idx := (d.recentNext - 1 - i + 2*n) % n
+		a := d.recent[idx].Anomaly
+		a.IncidentID = d.recent[idx].incident.Load()
+		out = append(out, a)
+	}
+	return out
+}
+
+// сбрасывает статистику сессии и возвращает ее в режим обучения
+func (d *Detector) Retrain(id string) error {
+	d.mu.Lock()
+	defer d.unlock()
+
+	s, ok := d.sessions[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	d.retrain(s)
+	return nil
+}
+
+func (d *Detector) retrain(s *session) {
+	d.closeIncident(s) // после переобучения отклонения считаются от новой статистики
+	s.stats = domain.NewRunningStats()
+	s.trainingMode = true
+	s.debounce.Reset()
+	s.window, s.windowNext = nil, 0
+	s.estimator = ""
+	s.cusum.Reset()
+	s.reference = nil
+	s.channels, s.cov = nil, nil
+	s.channelDebounce = nil
+	s.jointDebounce.Reset()
+	s.samples, s.samplesNext = nil, 0
+}
+
+// задает собственный K сессии для отклонений в обе стороны; k == 0 возвращает сессию к общим K.
+// Проверяемые стороны и абсолютные границы остаются общими
+func (d *Detector) SetSessionK(id string, k float64) error {
+	if k < 0 || math.IsNaN(k) || math.IsInf(k, 0) {
+		return fmt.Errorf("invalid K %g: must be a positive finite number", k)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, ok := d.sessions[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	s.k = k
+	return nil
+}
+
+// приостанавливает прием данных: точки отбрасываются и не влияют на статистику
+func (d *Detector) Pause() { d.paused.Store(true) }
+
+// возобновляет прием данных
+func (d *Detector) Resume() { d.paused.Store(false) }
+
+// сообщает, приостановлен ли прием, и сколько точек пропущено за все паузы
+func (d *Detector) Paused() (bool, uint64) { return d.paused.Load(), d.dropped.Load() }
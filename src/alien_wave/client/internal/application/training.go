@@ -0,0 +1,58 @@
+// github.com/lonmouth/alien_wave/client/internal/application/training.go
+package application
+
+import (
+	"log"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+)
+
+// обрабатывает точку в режиме обучения. Выход за абсолютные границы от статистики не зависит
+// и сохраняется как обычная аномалия; статистические отклонения обрабатываются по политике обучения.
+// Точка сравнивается со статистикой до ее добавления, иначе она размывала бы собственное отклонение
+func (d *Detector) train(s *session, point *transmitter.Transmission) {
+	checker := d.checkerFor(s)
+	switch {
+	case checker.Limits.Violated(point.Frequency):
+		d.saveAnomaly(s, point, true, false)
+	case d.training != domain.TrainingSuppress && s.stats.Count() >= 2 && checker.IsAnomaly(point.Frequency, s.stats):
+		if d.training == domain.TrainingShadow {
+			log.Printf("👻 Shadow anomaly during training | Session: %s | Freq: %.2f | μ=%.2f, σ=%.2f | n=%d",
+				s.id, point.Frequency, s.stats.Mean(), s.stats.STD(), s.stats.Count())
+			d.quietPoint(s)
+		} else {
+			d.saveAnomaly(s, point, false, true)
+		}
+	default:
+		d.quietPoint(s)
+	}
+
+	s.stats.Update(point.Frequency) // обновляет статистику
+	s.remember(point.Frequency)
+	s.trainChannels(point)
+	if s.stats.Count() < d.warmUp.MinSamples {
+		return
+	}
+
+	baseline := s.stats
+	if d.robust.Estimator != domain.EstimatorMean { // выбросы окна обучения не попадают в базовую линию
+		mean, std := d.robust.Estimate(s.window)
+		baseline = domain.NewRunningStatsFrom(s.stats.Count(), mean, std)
+	}
+	if !d.warmUp.Done(baseline) { // проверяет, завершено ли обучение
+		return
+	}
+
+	if baseline != s.stats {
+		log.Printf("Robust baseline (%s) | Session: %s | μ=%.2f→%.2f, σ=%.2f→%.2f",
+			d.robust.Estimator, s.id, s.stats.Mean(), baseline.Mean(), s.stats.STD(), baseline.STD())
+	}
+	s.stats = baseline
+	s.estimator = d.robust.Estimator
+	s.trainingMode = false
+	s.window, s.windowNext = nil, 0
+	d.closeIncident(s) // предварительные аномалии не продолжаются аномалиями по полной статистике
+	log.Printf("Training completed. μ=%.2f, σ=%.2f, n=%d, CI width=%.3f",
+		s.stats.Mean(), s.stats.STD(), s.stats.Count(), domain.CIWidth(s.stats))
+}
@@ -0,0 +1,49 @@
+// github.com/lonmouth/alien_wave/client/internal/config/watch.go
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// следит за файлом path, опрашивая его раз в interval, и отправляет сигнал в канал при изменении
+// времени модификации или размера. Канал закрывается после отмены ctx
+func WatchFile(ctx context.Context, path string, interval time.Duration) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last := stamp(path)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if cur := stamp(path); cur != last {
+					last = cur
+					select {
+					case ch <- struct{}{}:
+					default: // предыдущее уведомление еще не обработано
+					}
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func stamp(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{} // отсутствующий файл тоже состояние: его появление будет замечено
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
@@ -0,0 +1,151 @@
+// github.com/lonmouth/alien_wave/client/internal/config/webhook.go
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// настройки webhook-уведомлений об аномалиях; перечитываются без перезапуска
+type WebhookConfig struct {
+	URLs         []string      // адреса получателей (пусто - уведомления отключены)
+	Secret       string        // ключ подписи HMAC-SHA256
+	TemplateFile string        // файл с text/template для тела запроса (пусто - шаблон по умолчанию)
+	Timeout      time.Duration // таймаут одного запроса
+	MaxRetries   uint          // количество повторов при ошибке доставки
+	Backoff      time.Duration // задержка перед первым повтором
+	RatePerMin   float64       // лимит уведомлений в минуту на сессию (0 - без ограничений)
+	Burst        uint          // сколько уведомлений сессии можно отправить подряд
+	GroupMax     time.Duration // сколько открытый инцидент копит аномалии до отправки (0 - до завершения)
+}
+
+func loadWebhook(errs *[]error) WebhookConfig {
+	return WebhookConfig{
+		URLs:         envValue(errs, "WEBHOOK_URLS", "", parseList),
+		Secret:       getEnv("WEBHOOK_SECRET", ""),
+		TemplateFile: getEnv("WEBHOOK_TEMPLATE_FILE", ""),
+		Timeout:      envValue(errs, "WEBHOOK_TIMEOUT", "5s", parseDuration),
+		MaxRetries:   envValue(errs, "WEBHOOK_MAX_RETRIES", "3", parseUint),
+		Backoff:      envValue(errs, "WEBHOOK_BACKOFF", "500ms", parseDuration),
+		RatePerMin:   envValue(errs, "WEBHOOK_RATE_PER_MIN", "6", parseFloat),
+		Burst:        envValue(errs, "WEBHOOK_BURST", "3", parseUint),
+		GroupMax:     envValue(errs, "WEBHOOK_GROUP_MAX", "1m", parseDuration),
+	}
+}
+
+func (w *WebhookConfig) validate() error {
+	var errs []error
+	for _, u := range w.URLs {
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errs = append(errs, fmt.Errorf("WEBHOOK_URLS: invalid URL %q", u))
+		}
+	}
+	if w.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("WEBHOOK_TIMEOUT=%v: must be positive", w.Timeout))
+	}
+	if w.Backoff <= 0 {
+		errs = append(errs, fmt.Errorf("WEBHOOK_BACKOFF=%v: must be positive", w.Backoff))
+	}
+	if w.RatePerMin < 0 {
+		errs = append(errs, fmt.Errorf("WEBHOOK_RATE_PER_MIN=%g: must not be negative", w.RatePerMin))
+	}
+	if w.GroupMax < 0 {
+		errs = append(errs, fmt.Errorf("WEBHOOK_GROUP_MAX=%v: must not be negative", w.GroupMax))
+	}
+	return errors.Join(errs...)
+}
+
+// переменные окружения, которые переопределяет каждый флаг bindFlags
+var webhookFlagEnv = map[string]string{
+	"webhook-urls":        "WEBHOOK_URLS",
+	"webhook-template":    "WEBHOOK_TEMPLATE_FILE",
+	"webhook-timeout":     "WEBHOOK_TIMEOUT",
+	"webhook-max-retries": "WEBHOOK_MAX_RETRIES",
+	"webhook-backoff":     "WEBHOOK_BACKOFF",
+	"webhook-rate":        "WEBHOOK_RATE_PER_MIN",
+	"webhook-burst":       "WEBHOOK_BURST",
+	"webhook-group-max":   "WEBHOOK_GROUP_MAX",
+}
+
+// секрет намеренно не принимается флагом, чтобы не попадать в список процессов
+func (w *WebhookConfig) bindFlags(fs *flag.FlagSet) {
+	fs.Var((*listFlag)(&w.URLs), "webhook-urls", "адреса webhook через запятую (WEBHOOK_URLS)")
+	fs.StringVar(&w.TemplateFile, "webhook-template", w.TemplateFile, "файл шаблона тела webhook (WEBHOOK_TEMPLATE_FILE)")
+	fs.DurationVar(&w.Timeout, "webhook-timeout", w.Timeout, "таймаут запроса webhook (WEBHOOK_TIMEOUT)")
+	fs.UintVar(&w.MaxRetries, "webhook-max-retries", w.MaxRetries, "количество повторов webhook (WEBHOOK_MAX_RETRIES)")
+	fs.DurationVar(&w.Backoff, "webhook-backoff", w.Backoff, "задержка перед первым повтором (WEBHOOK_BACKOFF)")
+	fs.Float64Var(&w.RatePerMin, "webhook-rate", w.RatePerMin, "лимит уведомлений в минуту на сессию (WEBHOOK_RATE_PER_MIN)")
+	fs.UintVar(&w.Burst, "webhook-burst", w.Burst, "уведомлений сессии подряд (WEBHOOK_BURST)")
+	fs.DurationVar(&w.GroupMax, "webhook-group-max", w.GroupMax, "через сколько отправляется еще не завершенный инцидент (WEBHOOK_GROUP_MAX)")
+}
+
+func (w *WebhookConfig) redacted() [][2]string {
+	secret := ""
+	if w.Secret != "" {
+		secret = "xxxxx"
+	}
+	return [][2]string{
+		{"WEBHOOK_URLS", redactURLs(w.URLs)},
+		{"WEBHOOK_SECRET", secret},
+		{"WEBHOOK_TEMPLATE_FILE", w.TemplateFile},
+		{"WEBHOOK_TIMEOUT", w.Timeout.String()},
+		{"WEBHOOK_MAX_RETRIES", fmt.Sprint(w.MaxRetries)},
+		{"WEBHOOK_BACKOFF", w.Backoff.String()},
+		{"WEBHOOK_RATE_PER_MIN", strconv.FormatFloat(w.RatePerMin, 'g', -1, 64)},
+		{"WEBHOOK_BURST", fmt.Sprint(w.Burst)},
+		{"WEBHOOK_GROUP_MAX", w.GroupMax.String()},
+	}
+}
+
+// скрывает в адресах webhook все, кроме схемы и хоста: токены часто передаются
+// в userinfo, пути (Slack) или параметрах запроса
+func redactURLs(urls []string) string {
+	redacted := make([]string, len(urls))
+	for i, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil {
+			redacted[i] = "xxxxx"
+			continue
+		}
+		if u.User != nil {
+			u.User = url.User("xxxxx")
+		}
+		if u.Path != "" && u.Path != "/" {
+			u.Path, u.RawPath = "/xxxxx", ""
+		}
+		if u.RawQuery != "" {
+			u.RawQuery = "xxxxx"
+		}
+		u.Fragment, u.RawFragment = "", ""
+		redacted[i] = u.String()
+	}
+	return strings.Join(redacted, ",")
+}
+
+// разбирает список через запятую, пропуская пустые элементы
+func parseList(s string) ([]string, error) {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+// флаг со списком значений через запятую
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+
+func (l *listFlag) Set(s string) error {
+	v, err := parseList(s)
+	*l = v
+	return err
+}
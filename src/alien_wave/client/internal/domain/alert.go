@@ -0,0 +1,27 @@
+// github.com/lonmouth/alien_wave/client/internal/domain/alert.go
+package domain
+
+import "time"
+
+type AlertState string
+
+const (
+	AlertFiring   AlertState = "firing"   // условие правила выполняется
+	AlertResolved AlertState = "resolved" // условие перестало выполняться
+)
+
+// уведомление правила алертинга о смене состояния
+type Alert struct {
+	Rule      string     // имя правила
+	Severity  string     // важность из описания правила
+	State     AlertState // firing или resolved
+	SessionID string     // сессия, по которой сработало правило
+	StartsAt  time.Time  // момент срабатывания
+	EndsAt    time.Time  // момент разрешения (только для resolved)
+	Value     float64    // значение, на котором сработало условие
+	Summary   string     // человекочитаемое описание
+}
+
+type AlertNotifier interface {
+	NotifyAlert(a Alert) // метод для отправки уведомления об алерте; не должен блокировать вызывающего
+}
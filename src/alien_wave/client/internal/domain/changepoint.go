@@ -0,0 +1,90 @@
+// github.com/lonmouth/alien_wave/client/internal/domain/changepoint.go
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// вид изменения распределения сессии
+type ChangeKind string
+
+const (
+	ChangeMeanUp       ChangeKind = "mean_up"       // среднее выросло
+	ChangeMeanDown     ChangeKind = "mean_down"     // среднее снизилось
+	ChangeVarianceUp   ChangeKind = "variance_up"   // разброс вырос
+	ChangeVarianceDown ChangeKind = "variance_down" // разброс снизился
+)
+
+// точка изменения распределения сессии с оценками до и после
+type ChangePoint struct {
+	ID         uint64     // идентификатор, назначается репозиторием
+	SessionID  string     // уникальный идентификатор сессии
+	Kind       ChangeKind // что изменилось
+	StartedAt  time.Time  // время точки, с которой началось накопление отклонения
+	DetectedAt time.Time  // время точки, на которой изменение обнаружено
+	Points     uint       // количество точек после начала изменения
+	Statistic  float64    // значение статистики CUSUM в момент обнаружения
+	BeforeMean float64    // среднее базовой линии
+	BeforeSTD  float64    // стандартное отклонение базовой линии
+	AfterMean  float64    // среднее точек после начала изменения
+	AfterSTD   float64    // стандартное отклонение точек после начала изменения
+	Retrained  bool       // запущено ли переобучение сессии
+}
+
+// параметры обнаружения изменений
+type CUSUMConfig struct {
+	Drift     float64 // половина обнаруживаемого сдвига среднего в σ; для разброса обнаруживается изменение σ в 1+2·Drift раз
+	Threshold float64 // порог статистики (логарифма отношения правдоподобия) для обнаружения (0 - обнаружение отключено)
+	Retrain   bool    // переобучать сессию после обнаруженного изменения
+}
+
+// двусторонний CUSUM Пейджа по z-оценкам относительно базовой линии. Приращения - логарифмы отношения
+// правдоподобия: для среднего z-Drift, для разброса - нормального распределения с σ, измененным в r раз,
+// к исходному. При неизменном распределении среднее приращение отрицательно и статистики остаются около нуля
+type CUSUM struct {
+	stat [4]float64 // накопленные статистики в порядке cusumKinds
+	runs [4]uint    // сколько точек прошло с момента, когда статистика была нулевой
+}
+
+var cusumKinds = [4]ChangeKind{ChangeMeanUp, ChangeMeanDown, ChangeVarianceUp, ChangeVarianceDown}
+
+// обновляет статистики по z-оценке точки. При превышении порога возвращает вид изменения,
+// количество точек после его начала и значение статистики, после чего все статистики сбрасываются
+func (c *CUSUM) Update(z float64, cfg CUSUMConfig) (kind ChangeKind, run uint, stat float64, ok bool) {
+	if cfg.Threshold <= 0 || math.IsInf(z, 0) || math.IsNaN(z) {
+		return "", 0, 0, false
+	}
+	r := 1 + 2*cfg.Drift
+	for i, x := range [4]float64{z - cfg.Drift, -z - cfg.Drift, scaleLLR(z, r), scaleLLR(z, 1/r)} {
+		c.stat[i] = max(0, c.stat[i]+x)
+		if c.stat[i] == 0 {
+			c.runs[i] = 0
+		} else {
+			c.runs[i]++
+		}
+	}
+
+	best := -1
+	for i, s := range c.stat {
+		if s > cfg.Threshold && (best < 0 || s > c.stat[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "", 0, 0, false
+	}
+	kind, run, stat = cusumKinds[best], c.runs[best], c.stat[best]
+	c.Reset()
+	return kind, run, stat, true
+}
+
+// логарифм отношения правдоподобия N(0, r²) к N(0, 1) для z
+func scaleLLR(z, r float64) float64 {
+	return z*z/2*(1-1/(r*r)) - math.Log(r)
+}
+
+// сбрасывает накопленные статистики
+func (c *CUSUM) Reset() {
+	*c = CUSUM{}
+}
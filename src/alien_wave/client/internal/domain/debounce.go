@@ -0,0 +1,58 @@
+// github.com/lonmouth/alien_wave/client/internal/domain/debounce.go
+package domain
+
+// параметры подавления дребезга около порога
+type Debounce struct {
+	ExitRatio float64 // порог выхода из аномального состояния как доля порога входа (1 - без гистерезиса)
+	ConfirmN  uint    // сколько точек из ConfirmM последних должны превысить порог входа
+	ConfirmM  uint    // размер окна подтверждения (1 - подтверждение не требуется)
+}
+
+// без гистерезиса и подтверждения: каждая точка выше порога аномальна
+var NoDebounce = Debounce{ExitRatio: 1, ConfirmN: 1, ConfirmM: 1}
+
+// Debouncer хранит состояние подавления дребезга для одной последовательности точек.
+// Аномальное состояние начинается, когда ConfirmN из ConfirmM последних оценок выше порога входа,
+// и продолжается, пока оценка выше порога выхода
+type Debouncer struct {
+	hits   []bool // превышения порога входа для последних точек (кольцевой буфер)
+	next   int    // позиция следующей записи в hits
+	active bool   // находится ли последовательность в аномальном состоянии
+}
+
+// учитывает оценку очередной точки и сообщает, считается ли точка аномальной
+func (d *Debouncer) Observe(score, threshold float64, cfg Debounce) bool {
+	if d.active {
+		if score > threshold*cfg.ExitRatio {
+			return true
+		}
+		d.Reset()
+		return false
+	}
+
+	m := int(max(cfg.ConfirmM, 1))
+	if len(d.hits) != m { // размер окна изменился при перезагрузке конфигурации
+		d.hits, d.next = make([]bool, m), 0
+	}
+	d.hits[d.next] = score > threshold
+	d.next = (d.next + 1) % m
+
+	var n uint
+	for _, hit := range d.hits {
+		if hit {
+			n++
+		}
+	}
+	// подтверждающая точка сама должна быть выше порога, иначе аномалией оказалась бы нормальная точка
+	if score > threshold && n >= max(cfg.ConfirmN, 1) {
+		d.active = true
+	}
+	return d.active
+}
+
+// возвращает последовательность в нормальное состояние и очищает окно подтверждения
+func (d *Debouncer) Reset() {
+	d.active = false
+	clear(d.hits)
+	d.next = 0
+}
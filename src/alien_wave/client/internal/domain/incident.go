@@ -0,0 +1,64 @@
+// github.com/lonmouth/alien_wave/client/internal/domain/incident.go
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// инцидент (эпизод) - серия подряд идущих или близких аномалий одной сессии
+type Incident struct {
+	ID            uint64    // идентификатор, назначается репозиторием при первом сохранении
+	SessionID     string    // уникальный идентификатор сессии
+	StartedAt     time.Time // время первой аномалии эпизода
+	EndedAt       time.Time // время последней аномалии эпизода
+	Points        uint      // количество аномальных точек
+	PeakFrequency float64   // значение с наибольшим отклонением
+	PeakDeviation float64   // наибольшее отклонение в стандартных отклонениях
+	Direction     Direction // направление отклонений
+	Closed        bool      // эпизод завершен, новые аномалии в него не попадут
+
+	lastSeq uint64 // номер последней учтенной точки
+}
+
+// открывает инцидент по первой аномалии
+func NewIncident(a Anomaly) *Incident {
+	inc := &Incident{SessionID: a.SessionID, StartedAt: a.Timestamp}
+	inc.Add(a)
+	return inc
+}
+
+// добавляет аномалию в инцидент: обновляет границы, пик и направление. Аномалии нескольких каналов
+// одной точки приходят подряд и считаются одной аномальной точкой: точка определяется по номеру,
+// а без номера - по времени точки
+func (i *Incident) Add(a Anomaly) {
+	first := i.Points == 0
+	samePoint := a.Seq == i.lastSeq && a.Timestamp.Equal(i.EndedAt)
+	if a.Seq != 0 {
+		samePoint = a.Seq == i.lastSeq
+	}
+	if first || !samePoint {
+		i.Points++
+	}
+	i.EndedAt, i.lastSeq = a.Timestamp, a.Seq
+
+	if dev := Deviation(a); first || dev > i.PeakDeviation {
+		i.PeakFrequency, i.PeakDeviation = a.Frequency, dev
+	}
+
+	if a.Direction == "" { // у совместных аномалий по нескольким каналам направления нет
+		return
+	}
+	switch i.Direction {
+	case "":
+		i.Direction = a.Direction
+	case a.Direction:
+	default:
+		i.Direction = DirectionMixed
+	}
+}
+
+// модуль отклонения аномалии от среднего в стандартных отклонениях
+func Deviation(a Anomaly) float64 {
+	return math.Abs(a.ZScore)
+}
@@ -2,6 +2,7 @@
 package domain
 
 import (
+	"fmt"
 	"math"
 	"time"
 )
@@ -18,6 +19,15 @@ func NewRunningStats() *RunningStats {
 	return &RunningStats{}
 }
 
+// создает статистику, эквивалентную count значениям с заданными средним и стандартным отклонением
+func NewRunningStatsFrom(count uint, mean, std float64) *RunningStats {
+	s := &RunningStats{count: count, mean: mean}
+	if count > 1 {
+		s.m2 = std * std * float64(count-1)
+	}
+	return s
+}
+
 func (s *RunningStats) Update(x float64) {
 	s.count++
 	delta := x - s.mean                // разница между новым значением и текущим средним
@@ -37,23 +47,130 @@ func (s *RunningStats) STD() float64 {
 
 func (s *RunningStats) Count() uint { return s.count } // метод, возвращающий количество добавленных значений
 
+// Strategy - стратегия обнаружения аномалий. Точка считается аномальной, если ее оценка больше порога;
+// подавление дребезга (Debouncer) работает поверх оценки и порога любой стратегии
+type Strategy interface {
+	Score(value float64, stats *RunningStats) float64 // неотрицательная оценка отклонения точки
+	Threshold() float64                               // порог, выше которого точка аномальна
+}
+
+// алгоритмы обнаружения; записываются в каждую аномалию
+const (
+	ZScoreAlgorithm        = "zscore" // отклонение от среднего больше K стандартных отклонений
+	ZScoreAlgorithmVersion = "2"      // версия 2: односторонние и асимметричные пороги
+
+	HardLimitAlgorithm        = "hard_limit" // значение вне абсолютных границ при нормальном отклонении
+	HardLimitAlgorithmVersion = "1"
+)
+
+// стороны отклонения, которые проверяет AnomalyChecker
+type Side string
+
+const (
+	SideBoth  Side = "both"  // отклонения вверх и вниз
+	SideUpper Side = "upper" // только отклонения вверх
+	SideLower Side = "lower" // только отклонения вниз
+)
+
+// разбирает название стороны: both, upper или lower
+func ParseSide(name string) (Side, error) {
+	switch s := Side(name); s {
+	case SideBoth, SideUpper, SideLower:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown side %q (want both, upper or lower)", name)
+}
+
+// абсолютные границы частоты: значение за границей аномально независимо от статистики
+type Limits struct {
+	Min, Max       float64
+	HasMin, HasMax bool // заданы ли границы
+}
+
+// выходит ли значение за заданные границы
+func (l Limits) Violated(value float64) bool {
+	return (l.HasMin && value < l.Min) || (l.HasMax && value > l.Max)
+}
+
 type AnomalyChecker struct { // структура для проверки значений на наличие аномалий
-	K float64
+	K      float64 // коэффициент по умолчанию
+	KHigh  float64 // коэффициент для отклонений вверх
+	KLow   float64 // коэффициент для отклонений вниз
+	Side   Side    // какие отклонения проверяются
+	Limits Limits  // абсолютные границы частоты
 }
 
 func NewAnomalyChecker(k float64) *AnomalyChecker { // функция-конструктор, создающая новый экземпляр AnomalyChecker с заданным коэффициентом k
-	return &AnomalyChecker{K: k}
+	return &AnomalyChecker{K: k, KHigh: k, KLow: k, Side: SideBoth}
 }
 
-func (c *AnomalyChecker) IsAnomaly(value float64, stats *RunningStats) bool { // сравнивает отклонение значения от среднего с порогом, определяемым как K стандартных отклонений
-	return math.Abs(value-stats.Mean()) > c.K*stats.STD()
+// копия checker с одинаковым коэффициентом k в обе стороны; стороны и границы сохраняются
+func (c *AnomalyChecker) WithK(k float64) *AnomalyChecker {
+	cp := *c
+	cp.K, cp.KHigh, cp.KLow = k, k, k
+	return &cp
 }
 
+// коэффициент для отклонения в направлении dir
+func (c *AnomalyChecker) KFor(dir Direction) float64 {
+	if dir == DirectionLow {
+		return c.KLow
+	}
+	return c.KHigh
+}
+
+// проверяется ли отклонение в направлении dir
+func (c *AnomalyChecker) checks(dir Direction) bool {
+	switch c.Side {
+	case SideUpper:
+		return dir == DirectionHigh
+	case SideLower:
+		return dir == DirectionLow
+	}
+	return true
+}
+
+func (c *AnomalyChecker) IsAnomaly(value float64, stats *RunningStats) bool { // значение вне абсолютных границ или отклонение больше K стандартных отклонений в проверяемую сторону
+	return c.Limits.Violated(value) || c.Score(value, stats) > c.Threshold()
+}
+
+// отклонение в единицах K стороны отклонения: 1 - ровно на пороге; непроверяемая сторона дает 0.
+// При нулевом σ любое отличие от среднего бесконечно велико
+func (c *AnomalyChecker) Score(value float64, stats *RunningStats) float64 {
+	dir := DirectionOf(value, stats.Mean())
+	if value == stats.Mean() || !c.checks(dir) {
+		return 0
+	}
+	return math.Abs(ZScore(value, stats.Mean(), stats.STD())) / c.KFor(dir)
+}
+
+// оценка совместной проверки каналов: эквивалентная z-оценка z в единицах K стороны dir, в которую
+// отклонилась frequency; непроверяемая сторона дает 0
+func (c *AnomalyChecker) JointScore(z float64, dir Direction) float64 {
+	if !c.checks(dir) {
+		return 0
+	}
+	return math.Abs(z) / c.KFor(dir)
+}
+
+// оценка нормирована на K, поэтому порог всегда равен 1
+func (c *AnomalyChecker) Threshold() float64 { return 1 }
+
 type Anomaly struct {
-	SessionID    string    // уникальный идентификатор сессии
-	Frequency    float64   // значение частоты, которое считается аномальным
-	Timestamp    time.Time // время, когда была обнаружена аномалия
-	ExpectedMean float64   // ожидаемое среднее значение
-	ExpectedSTD  float64   // ожидаемое стандартное отклонение
-	K            float64   // коэффициент использованный для обнаружения аномалии
+	SessionID        string    // уникальный идентификатор сессии
+	Channel          string    // канал измерений ("" - frequency, JointChannel - совместная проверка всех каналов)
+	Frequency        float64   // аномальное значение канала; для совместной проверки - значение frequency
+	Timestamp        time.Time // время, когда была обнаружена аномалия
+	Seq              uint64    // номер точки в сессии (0 - сервер не нумерует точки; в базе не хранится)
+	ExpectedMean     float64   // ожидаемое среднее значение
+	ExpectedSTD      float64   // ожидаемое стандартное отклонение
+	K                float64   // коэффициент использованный для обнаружения аномалии
+	ConfigVersion    uint64    // версия конфигурации, действовавшей при обнаружении
+	ZScore           float64   // отклонение от среднего в стандартных отклонениях со знаком; для совместной проверки - EquivalentZ
+	Direction        Direction // направление отклонения (пусто для совместной проверки)
+	Severity         Severity  // уровень важности по модулю ZScore
+	Algorithm        string    // алгоритм, обнаруживший аномалию
+	AlgorithmVersion string    // версия алгоритма
+	IncidentID       uint64    // инцидент, к которому относится аномалия (0 - не определен)
+	Provisional      bool      // найдена во время обучения по неполной статистике
 }
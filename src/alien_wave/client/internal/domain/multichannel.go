@@ -0,0 +1,117 @@
+// github.com/lonmouth/alien_wave/client/internal/domain/multichannel.go
+package domain
+
+import "math"
+
+// совместная проверка всех каналов точки по расстоянию Махаланобиса
+const (
+	MahalanobisAlgorithm        = "mahalanobis"
+	MahalanobisAlgorithmVersion = "1"
+)
+
+// имя основного канала Transmission.frequency в выборках и выводе; в Anomaly.Channel ему соответствует пустая строка
+const FrequencyChannel = "frequency"
+
+// канал совместных аномалий по всем каналам точки
+const JointChannel = "joint"
+
+// имя канала аномалии для вывода
+func (a Anomaly) ChannelName() string {
+	if a.Channel == "" {
+		return FrequencyChannel
+	}
+	return a.Channel
+}
+
+// онлайн-оценка среднего и ковариационной матрицы векторов (многомерный алгоритм Уэлфорда)
+type Covariance struct {
+	count uint
+	mean  []float64
+	m2    [][]float64 // сумма произведений отклонений
+}
+
+func NewCovariance(dim int) *Covariance {
+	m2 := make([][]float64, dim)
+	for i := range m2 {
+		m2[i] = make([]float64, dim)
+	}
+	return &Covariance{mean: make([]float64, dim), m2: m2}
+}
+
+// размерность векторов
+func (c *Covariance) Dim() int { return len(c.mean) }
+
+func (c *Covariance) Count() uint { return c.count }
+
+// добавляет вектор; длина x должна совпадать с Dim
+func (c *Covariance) Update(x []float64) {
+	c.count++
+	delta := make([]float64, len(x))
+	for i, v := range x {
+		delta[i] = v - c.mean[i]
+		c.mean[i] += delta[i] / float64(c.count)
+	}
+	for i := range x {
+		for j := range x {
+			c.m2[i][j] += delta[i] * (x[j] - c.mean[j])
+		}
+	}
+}
+
+// статистика i-го канала
+func (c *Covariance) Stats(i int) *RunningStats {
+	s := &RunningStats{count: c.count, mean: c.mean[i]}
+	if c.count > 1 {
+		s.m2 = c.m2[i][i]
+	}
+	return s
+}
+
+// квадрат расстояния Махаланобиса от x до среднего. ok == false, если векторов меньше, чем нужно
+// для оценки матрицы, или матрица вырождена (например, каналы линейно зависимы)
+func (c *Covariance) Mahalanobis(x []float64) (d2 float64, ok bool) {
+	n := c.Dim()
+	if c.count <= uint(n) {
+		return 0, false
+	}
+	// разложение Холецкого ковариационной матрицы S = L·Lᵀ
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, i+1)
+		for j := 0; j <= i; j++ {
+			sum := c.m2[i][j] / float64(c.count-1)
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if sum <= 1e-12*c.m2[i][i]/float64(c.count-1) { // остаток дисперсии канала пренебрежимо мал
+					return 0, false
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+	// d² = |y|², где L·y = x - μ
+	y := make([]float64, n)
+	for i := range y {
+		sum := x[i] - c.mean[i]
+		for k := 0; k < i; k++ {
+			sum -= l[i][k] * y[k]
+		}
+		y[i] = sum / l[i][i]
+		d2 += y[i] * y[i]
+	}
+	return d2, true
+}
+
+// переводит квадрат расстояния Махаланобиса в dim измерениях в модуль z-оценки с той же вероятностью
+// двустороннего превышения для одного нормального значения. Распределение χ² приближается по Уилсону-Хилферти;
+// так совместная проверка использует те же K и пороги важности, что и проверка одного канала
+func EquivalentZ(d2 float64, dim int) float64 {
+	k := 2 / (9 * float64(dim))
+	z := (math.Cbrt(d2/float64(dim)) - (1 - k)) / math.Sqrt(k)
+	tail := math.Erfc(z/math.Sqrt2) / 2
+	return math.Sqrt2 * math.Erfcinv(tail)
+}
@@ -0,0 +1,17 @@
+// github.com/lonmouth/alien_wave/client/internal/domain/notifier.go
+package domain
+
+type AnomalyNotifier interface {
+	Notify(a Anomaly) // метод для уведомления об аномалии; не должен блокировать обработку потока
+}
+
+// IncidentNotifier - необязательное расширение AnomalyNotifier: детектор сообщает о завершении сохраненного
+// инцидента, чтобы получатель мог отправить его аномалии (Anomaly.IncidentID == ID) одним уведомлением
+type IncidentNotifier interface {
+	NotifyIncidentClosed(inc Incident) // не должен блокировать обработку потока
+}
+
+// ChangePointNotifier - необязательное расширение AnomalyNotifier для уведомлений об изменении распределения
+type ChangePointNotifier interface {
+	NotifyChangePoint(cp ChangePoint) // не должен блокировать обработку потока
+}
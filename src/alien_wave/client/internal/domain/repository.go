@@ -1,6 +1,43 @@
 // github.com/lonmouth/alien_wave/client/internal/domain/repository.go
 package domain
 
+import "time"
+
 type AnomalyRepository interface {
-	Save(a Anomaly) error // метод для сохранения аномалий
+	Save(a Anomaly) error                   // метод для сохранения аномалий
+	SaveIncident(i *Incident) error         // метод для создания (ID == 0) или обновления инцидента
+	SaveChangePoint(cp *ChangePoint) error  // метод для сохранения точки изменения распределения
+	SaveSessionEvent(e *SessionEvent) error // метод для сохранения события потока сессии
+}
+
+// условия выборки аномалий; нулевые значения полей не ограничивают выборку
+type AnomalyFilter struct {
+	SessionID string    // идентификатор сессии
+	From      time.Time // не раньше этого момента
+	To        time.Time // не позже этого момента
+	Limit     int       // максимальное количество записей
+
+	MinSeverity Severity // не ниже этого уровня важности (только для аномалий)
+	Channel     string   // только аномалии канала: FrequencyChannel, имя канала или JointChannel
+	BySeverity  bool     // сортировать по убыванию важности (инциденты - по пиковому отклонению), затем по времени
+}
+
+// сводка по одной сессии
+type SessionSummary struct {
+	SessionID    string    // идентификатор сессии
+	Anomalies    uint      // количество аномалий
+	FirstSeen    time.Time // время первой аномалии
+	LastSeen     time.Time // время последней аномалии
+	MinFrequency float64   // минимальная аномальная частота
+	MaxFrequency float64   // максимальная аномальная частота
+	ExpectedMean float64   // среднее ожидаемое значение по аномалиям сессии
+	ExpectedSTD  float64   // среднее ожидаемое стандартное отклонение по аномалиям сессии
+}
+
+type AnomalyReader interface {
+	Find(f AnomalyFilter) ([]Anomaly, error)               // метод для выборки аномалий, новые первыми
+	Sessions() ([]SessionSummary, error)                   // метод для получения сводки по сессиям
+	Incidents(f AnomalyFilter) ([]Incident, error)         // метод для выборки инцидентов, новые первыми
+	ChangePoints(f AnomalyFilter) ([]ChangePoint, error)   // метод для выборки точек изменения, новые первыми
+	SessionEvents(f AnomalyFilter) ([]SessionEvent, error) // метод для выборки событий потока сессий, новые первыми
 }
@@ -0,0 +1,158 @@
+// github.com/lonmouth/alien_wave/client/internal/domain/robust.go
+package domain
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// способ оценки базовой линии (μ, σ) по окну обучения
+type Estimator string
+
+const (
+	EstimatorMean      Estimator = "mean"       // обычные среднее и стандартное отклонение по всем точкам
+	EstimatorSigmaClip Estimator = "sigma_clip" // итеративное отсечение точек дальше ClipK·σ
+	EstimatorMAD       Estimator = "mad"        // медиана и 1.4826·MAD
+	EstimatorHuber     Estimator = "huber"      // M-оценка Хьюбера для среднего, σ по MAD
+	EstimatorTrimmed   Estimator = "trimmed"    // среднее без TrimFraction точек с каждого края, σ по винзоризованной выборке
+)
+
+// разбирает название способа оценки
+func ParseEstimator(name string) (Estimator, error) {
+	switch e := Estimator(name); e {
+	case EstimatorMean, EstimatorSigmaClip, EstimatorMAD, EstimatorHuber, EstimatorTrimmed:
+		return e, nil
+	}
+	return "", fmt.Errorf("unknown estimator %q (want mean, sigma_clip, mad, huber or trimmed)", name)
+}
+
+// коэффициент, приводящий MAD к σ нормального распределения
+const madToSigma = 1.4826
+
+// параметры устойчивой оценки базовой линии
+type Robust struct {
+	Estimator    Estimator
+	ClipK        float64 // порог отсечения в σ для sigma_clip
+	ClipIter     uint    // максимальное количество итераций sigma_clip
+	HuberC       float64 // порог Хьюбера в σ
+	TrimFraction float64 // доля точек, отбрасываемых с каждого края для trimmed, [0, 0.5)
+}
+
+// оценивает среднее и стандартное отклонение по значениям; values не изменяется
+func (r Robust) Estimate(values []float64) (mean, std float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	switch r.Estimator {
+	case EstimatorSigmaClip:
+		return sigmaClip(values, r.ClipK, r.ClipIter)
+	case EstimatorMAD:
+		sorted := sortedCopy(values)
+		med := median(sorted)
+		return med, madScale(sorted, med)
+	case EstimatorHuber:
+		return huber(values, r.HuberC)
+	case EstimatorTrimmed:
+		return trimmed(values, r.TrimFraction)
+	}
+	return meanSTD(values)
+}
+
+func meanSTD(values []float64) (float64, float64) {
+	s := NewRunningStats()
+	for _, v := range values {
+		s.Update(v)
+	}
+	return s.Mean(), s.STD()
+}
+
+func sigmaClip(values []float64, k float64, iterations uint) (float64, float64) {
+	kept := slices.Clone(values)
+	mean, std := meanSTD(kept)
+	for i := uint(0); i < iterations && std > 0; i++ {
+		next := kept[:0:0]
+		for _, v := range kept {
+			if math.Abs(v-mean) <= k*std {
+				next = append(next, v)
+			}
+		}
+		if len(next) == len(kept) || len(next) < 2 { // сошлось или отсекать больше нечего
+			break
+		}
+		kept = next
+		mean, std = meanSTD(kept)
+	}
+	return mean, std
+}
+
+// M-оценка положения Хьюбера методом взвешенных наименьших квадратов; масштаб фиксирован по MAD
+func huber(values []float64, c float64) (float64, float64) {
+	sorted := sortedCopy(values)
+	mu := median(sorted)
+	scale := madScale(sorted, mu)
+	if scale == 0 {
+		return mu, scale
+	}
+	for range 50 {
+		var sw, swx float64
+		for _, v := range values {
+			w := 1.0
+			if r := math.Abs(v-mu) / scale; r > c {
+				w = c / r
+			}
+			sw += w
+			swx += w * v
+		}
+		next := swx / sw
+		if math.Abs(next-mu) < 1e-9*scale {
+			mu = next
+			break
+		}
+		mu = next
+	}
+	return mu, scale
+}
+
+// σ по медианному абсолютному отклонению; если больше половины точек совпадает, MAD равен 0
+// и используется обычное стандартное отклонение
+func madScale(sorted []float64, med float64) float64 {
+	dev := make([]float64, len(sorted))
+	for i, v := range sorted {
+		dev[i] = math.Abs(v - med)
+	}
+	slices.Sort(dev)
+	if mad := median(dev); mad > 0 {
+		return madToSigma * mad
+	}
+	_, std := meanSTD(sorted)
+	return std
+}
+
+// усеченное среднее; σ считается по винзоризованной выборке, где крайние значения заменены
+// ближайшими оставшимися, иначе отбрасывание хвостов занижало бы разброс
+func trimmed(values []float64, fraction float64) (float64, float64) {
+	sorted := sortedCopy(values)
+	n := len(sorted)
+	cut := min(int(float64(n)*fraction), (n-1)/2)
+	mean, _ := meanSTD(sorted[cut : n-cut])
+	for i := 0; i < cut; i++ {
+		sorted[i], sorted[n-1-i] = sorted[cut], sorted[n-1-cut]
+	}
+	_, std := meanSTD(sorted)
+	return mean, std
+}
+
+func sortedCopy(values []float64) []float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted
+}
+
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,91 @@
+// github.com/lonmouth/alien_wave/client/internal/domain/sequence.go
+package domain
+
+import "time"
+
+// вид события сессии
+type SessionEventKind string
+
+const (
+	EventGap       SessionEventKind = "gap"       // пропущены точки: номер больше ожидаемого
+	EventDuplicate SessionEventKind = "duplicate" // точка с уже полученным номером
+	EventReorder   SessionEventKind = "reorder"   // пропущенная ранее точка пришла с опозданием
+
+	EventSessionStart  SessionEventKind = "session_start"  // сервер объявил начало сессии
+	EventSessionResume SessionEventKind = "session_resume" // сессия продолжена после переподключения
+	EventSessionEnd    SessionEventKind = "session_end"    // сервер завершил сессию; причина в Detail
+	EventLinkLost      SessionEventKind = "link_lost"      // от сервера нет ни точек, ни heartbeat: соединение считается потерянным
+)
+
+// событие потока сессии, не связанное со значениями точек
+type SessionEvent struct {
+	ID        uint64           // идентификатор, назначается репозиторием
+	SessionID string           // уникальный идентификатор сессии
+	Kind      SessionEventKind // что произошло
+	At        time.Time        // время точки, на которой событие обнаружено
+	Seq       uint64           // номер этой точки
+	Expected  uint64           // ожидавшийся номер (для gap и reorder)
+	Count     uint64           // количество пропущенных точек (для gap)
+	Detail    string           // пояснение: причина завершения сессии, параметры генератора
+}
+
+// сколько пропущенных номеров помнит SequenceTracker, чтобы отличать опоздавшие точки от повторов
+const maxMissingSeq = 1024
+
+// отслеживает номера точек сессии: пропуски, повторы и нарушения порядка
+type SequenceTracker struct {
+	last    uint64              // наибольший полученный номер (0 - номеров еще не было)
+	missing map[uint64]struct{} // пропущенные номера, которые еще могут прийти
+	order   []uint64            // пропущенные номера в порядке обнаружения для ограничения размера missing
+}
+
+// учитывает номер очередной точки и возвращает вид события (пусто, если номер ожидаемый).
+// Для gap expected - первый пропущенный номер и count - количество пропущенных, для reorder expected - следующий
+// ожидаемый номер. Номер 0 означает, что сервер точки не нумерует, и не проверяется
+func (t *SequenceTracker) Observe(seq uint64) (kind SessionEventKind, expected, count uint64) {
+	switch {
+	case seq == 0:
+		return "", 0, 0
+	case t.last == 0 || seq == t.last+1:
+		t.last = seq
+		return "", 0, 0
+	case seq > t.last+1:
+		expected, count = t.last+1, seq-t.last-1
+		start := expected
+		if count > maxMissingSeq {
+			start = seq - maxMissingSeq
+		}
+		for n := start; n < seq; n++ {
+			t.forget()
+			t.remember(n)
+		}
+		t.last = seq
+		return EventGap, expected, count
+	}
+	if _, ok := t.missing[seq]; ok {
+		delete(t.missing, seq)
+		return EventReorder, t.last + 1, 0
+	}
+	return EventDuplicate, t.last + 1, 0
+}
+
+// наибольший полученный номер (0 - номеров еще не было)
+func (t *SequenceTracker) Last() uint64 {
+	return t.last
+}
+
+func (t *SequenceTracker) remember(seq uint64) {
+	if t.missing == nil {
+		t.missing = make(map[uint64]struct{})
+	}
+	t.missing[seq] = struct{}{}
+	t.order = append(t.order, seq)
+}
+
+// освобождает место для нового пропущенного номера, удаляя самый старый
+func (t *SequenceTracker) forget() {
+	for len(t.order) >= maxMissingSeq {
+		delete(t.missing, t.order[0])
+		t.order = t.order[1:]
+	}
+}
@@ -0,0 +1,94 @@
+// github.com/lonmouth/alien_wave/client/internal/domain/severity.go
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// направление отклонения от ожидаемого среднего
+type Direction string
+
+const (
+	DirectionHigh  Direction = "high"  // значение выше среднего
+	DirectionLow   Direction = "low"   // значение ниже среднего
+	DirectionMixed Direction = "mixed" // в инциденте есть отклонения в обе стороны
+)
+
+// уровень важности аномалии; уровни упорядочены, поэтому их можно сравнивать и сортировать
+type Severity uint8
+
+const (
+	SeverityNone     Severity = iota // уровень не определен
+	SeverityMinor                    // отклонение меньше порога major
+	SeverityMajor                    // отклонение не меньше порога major
+	SeverityCritical                 // отклонение не меньше порога critical
+)
+
+var severityNames = [...]string{"", "minor", "major", "critical"}
+
+func (s Severity) String() string {
+	if int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return fmt.Sprintf("severity(%d)", uint8(s))
+}
+
+// разбирает название уровня: minor, major или critical
+func ParseSeverity(name string) (Severity, error) {
+	for i, n := range severityNames {
+		if i > 0 && n == name {
+			return Severity(i), nil
+		}
+	}
+	return SeverityNone, fmt.Errorf("unknown severity %q (want minor, major or critical)", name)
+}
+
+// уровень сериализуется названием в JSON, YAML и шаблонах
+func (s Severity) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	v, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// пороги модуля z-оценки для уровней важности
+type SeverityScale struct {
+	Major    float64
+	Critical float64
+}
+
+// определяет уровень важности по z-оценке
+func (sc SeverityScale) Classify(z float64) Severity {
+	switch z = math.Abs(z); {
+	case z >= sc.Critical:
+		return SeverityCritical
+	case z >= sc.Major:
+		return SeverityMajor
+	default:
+		return SeverityMinor
+	}
+}
+
+// отклонение value от mean в стандартных отклонениях со знаком; при нулевом σ - бесконечность со знаком отклонения
+func ZScore(value, mean, std float64) float64 {
+	if std == 0 {
+		if value < mean {
+			return math.Inf(-1)
+		}
+		return math.Inf(1)
+	}
+	return (value - mean) / std
+}
+
+// направление отклонения value от mean
+func DirectionOf(value, mean float64) Direction {
+	if value < mean {
+		return DirectionLow
+	}
+	return DirectionHigh
+}
@@ -0,0 +1,50 @@
+// github.com/lonmouth/alien_wave/client/internal/domain/training.go
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// что делать с отклонениями, найденными во время обучения, пока статистика еще не устоялась
+type TrainingPolicy string
+
+const (
+	TrainingSuppress    TrainingPolicy = "suppress"    // не проверять точки до окончания обучения
+	TrainingShadow      TrainingPolicy = "shadow"      // только записать отклонение в лог
+	TrainingProvisional TrainingPolicy = "provisional" // сохранить аномалию с признаком Provisional
+)
+
+// разбирает название политики: suppress, shadow или provisional
+func ParseTrainingPolicy(name string) (TrainingPolicy, error) {
+	switch p := TrainingPolicy(name); p {
+	case TrainingSuppress, TrainingShadow, TrainingProvisional:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown training policy %q (want suppress, shadow or provisional)", name)
+}
+
+// z для 95% доверительного интервала среднего
+const ci95 = 1.96
+
+// критерии завершения обучения; должны выполняться все заданные
+type WarmUp struct {
+	MinSamples uint    // минимальное количество точек
+	MaxCIWidth float64 // максимальная ширина 95% доверительного интервала среднего (0 - не проверяется)
+}
+
+// ширина 95% доверительного интервала среднего; пока точек меньше двух - бесконечность
+func CIWidth(stats *RunningStats) float64 {
+	if stats.Count() < 2 {
+		return math.Inf(1)
+	}
+	return 2 * ci95 * stats.STD() / math.Sqrt(float64(stats.Count()))
+}
+
+// выполнены ли критерии завершения обучения
+func (w WarmUp) Done(stats *RunningStats) bool {
+	if stats.Count() < max(w.MinSamples, 2) { // по одной точке стандартное отклонение равно 0
+		return false
+	}
+	return w.MaxCIWidth <= 0 || CIWidth(stats) <= w.MaxCIWidth
+}
@@ -0,0 +1,287 @@
+// github.com/lonmouth/alien_wave/client/internal/infrastructure/admin/server.go
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/lonmouth/alien_wave/client/internal/application"
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+	"github.com/lonmouth/alien_wave/client/internal/infrastructure/grpc"
+)
+
+// HTTP API для просмотра и управления работающим клиентом
+//
+//	GET  /sessions              - активные сессии: μ, σ, количество точек, режим обучения
+//	POST /sessions/{id}/retrain - вернуть сессию в режим обучения
+//	PUT  /sessions/{id}/k       - задать K сессии: {"k": 3}; {"k": 0} возвращает общий K
+//	GET  /anomalies?limit=N     - последние аномалии; min_severity=major оставляет только major и critical
+//	GET  /ingestion             - состояние приема данных
+//	POST /ingestion/pause       - приостановить прием данных
+//	POST /ingestion/resume      - возобновить прием данных
+//	GET  /stream                - состояние потока от сервера: сессия, подтвержденная точка, скорость
+//	PUT  /stream/flow           - попросить сервер изменить скорость: {"interval": "500ms", "paused": false}
+type Server struct {
+	detector *application.Detector
+	link     atomic.Pointer[grpc.Link] // nil, пока поток не запущен; задается после Start
+	mux      *http.ServeMux
+	srv      *http.Server
+}
+
+func NewServer(addr string, detector *application.Detector) *Server {
+	mux := http.NewServeMux()
+	s := &Server{detector: detector, mux: mux}
+
+	mux.HandleFunc("GET /sessions", s.listSessions)
+	mux.HandleFunc("POST /sessions/{id}/retrain", s.retrain)
+	mux.HandleFunc("PUT /sessions/{id}/k", s.setK)
+	mux.HandleFunc("GET /anomalies", s.recentAnomalies)
+	mux.HandleFunc("GET /ingestion", s.ingestion)
+	mux.HandleFunc("POST /ingestion/pause", s.pause)
+	mux.HandleFunc("POST /ingestion/resume", s.resume)
+	mux.HandleFunc("GET /stream", s.stream)
+	mux.HandleFunc("PUT /stream/flow", s.setFlow)
+
+	s.srv = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Handle регистрирует дополнительный обработчик на том же адресе
+func (s *Server) Handle(pattern string, h http.Handler) {
+	s.mux.Handle(pattern, h)
+}
+
+// SetLink задает поток от сервера, которым управляют /stream; можно вызывать после Start
+func (s *Server) SetLink(l *grpc.Link) {
+	s.link.Store(l)
+}
+
+// начинает принимать запросы; ошибка прослушивания адреса возвращается сразу
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := s.srv.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Admin API error: %v", err)
+		}
+	}()
+	log.Printf("Admin API is listening on %s", lis.Addr())
+	return nil
+}
+
+// останавливает сервер, дожидаясь завершения активных запросов
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
+
+func (s *Server) listSessions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.detector.Sessions())
+}
+
+func (s *Server) retrain(w http.ResponseWriter, r *http.Request) {
+	if err := s.detector.Retrain(r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) setK(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		K *float64 `json:"k"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.K == nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": `expected body {"k": <number>}`})
+		return
+	}
+	if err := s.detector.SetSessionK(r.PathValue("id"), *body.K); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) recentAnomalies(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "limit must be a non-negative integer"})
+			return
+		}
+		limit = n
+	}
+	var minSeverity domain.Severity
+	if v := r.URL.Query().Get("min_severity"); v != "" {
+		sev, err := domain.ParseSeverity(v)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		minSeverity = sev
+	}
+
+	anomalies := s.detector.RecentAnomalies(0)
+	views := make([]anomalyView, 0, min(limit, len(anomalies)))
+	for _, a := range anomalies {
+		if a.Severity < minSeverity {
+			continue
+		}
+		if limit > 0 && len(views) == limit {
+			break
+		}
+		view := anomalyView{
+			SessionID:        a.SessionID,
+			Channel:          a.ChannelName(),
+			Frequency:        a.Frequency,
+			Timestamp:        a.Timestamp,
+			ExpectedMean:     a.ExpectedMean,
+			ExpectedSTD:      a.ExpectedSTD,
+			K:                a.K,
+			ConfigVersion:    a.ConfigVersion,
+			Direction:        string(a.Direction),
+			Severity:         a.Severity.String(),
+			Algorithm:        a.Algorithm,
+			AlgorithmVersion: a.AlgorithmVersion,
+			IncidentID:       a.IncidentID,
+			Provisional:      a.Provisional,
+		}
+		if !math.IsInf(a.ZScore, 0) {
+			view.ZScore = &a.ZScore
+		}
+		views = append(views, view)
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+// представление аномалии в ответах API
+type anomalyView struct {
+	SessionID     string    `json:"session_id"`
+	Channel       string    `json:"channel"`
+	Frequency     float64   `json:"frequency"`
+	Timestamp     time.Time `json:"timestamp"`
+	ExpectedMean  float64   `json:"expected_mean"`
+	ExpectedSTD   float64   `json:"expected_std"`
+	K             float64   `json:"k"`
+	ConfigVersion uint64    `json:"config_version"`
+
+	ZScore           *float64 `json:"z_score"` // null, если σ была нулевой
+	Direction        string   `json:"direction"`
+	Severity         string   `json:"severity"`
+	Algorithm        string   `json:"algorithm"`
+	AlgorithmVersion string   `json:"algorithm_version"`
+	IncidentID       uint64   `json:"incident_id,omitempty"`
+	Provisional      bool     `json:"provisional"`
+}
+
+func (s *Server) ingestion(w http.ResponseWriter, r *http.Request) {
+	paused, dropped := s.detector.Paused()
+	writeJSON(w, http.StatusOK, map[string]any{"paused": paused, "dropped": dropped})
+}
+
+func (s *Server) pause(w http.ResponseWriter, r *http.Request) {
+	s.detector.Pause()
+	log.Println("Ingestion paused via admin API")
+	s.ingestion(w, r)
+}
+
+func (s *Server) resume(w http.ResponseWriter, r *http.Request) {
+	s.detector.Resume()
+	log.Println("Ingestion resumed via admin API")
+	s.ingestion(w, r)
+}
+
+func (s *Server) stream(w http.ResponseWriter, r *http.Request) {
+	link := s.link.Load()
+	if link == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "stream is not running"})
+		return
+	}
+	st := link.State()
+	interval := "server"
+	if st.Interval != 0 {
+		interval = st.Interval.String()
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"connected":    st.Connected,
+		"subscription": st.Subscription,
+		"session_id":   st.SessionID,
+		"acked":        st.Acked,
+		"interval":     interval,
+		"paused":       st.Paused,
+		"reconnects":   st.Reconnects,
+	})
+}
+
+func (s *Server) setFlow(w http.ResponseWriter, r *http.Request) {
+	link := s.link.Load()
+	if link == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "stream is not running"})
+		return
+	}
+	var body struct {
+		Interval *string `json:"interval"` // "" или "0" - интервал сервера
+		Paused   *bool   `json:"paused"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": `expected body {"interval": "500ms", "paused": false}`})
+		return
+	}
+	st := link.State()
+	interval, paused := st.Interval, st.Paused
+	if body.Interval != nil {
+		interval = 0
+		if *body.Interval != "" {
+			d, err := time.ParseDuration(*body.Interval)
+			if err != nil || d < 0 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "interval must be a non-negative duration"})
+				return
+			}
+			interval = d
+		}
+	}
+	if body.Paused != nil {
+		paused = *body.Paused
+	}
+	if err := link.SetFlow(interval, paused); err != nil {
+		code := http.StatusBadGateway
+		if errors.Is(err, grpc.ErrSubscription) {
+			code = http.StatusConflict
+		}
+		writeJSON(w, code, map[string]string{"error": err.Error()})
+		return
+	}
+	log.Printf("Stream flow changed via admin API: interval=%v, paused=%t", interval, paused)
+	s.stream(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Admin API encode error: %v", err)
+	}
+}
+
+// преобразует ошибку детектора в HTTP-статус
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, application.ErrSessionNotFound) {
+		status = http.StatusNotFound
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
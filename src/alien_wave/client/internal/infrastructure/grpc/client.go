@@ -4,31 +4,34 @@ package grpc
 import (
 	"context"
 
-	transmitter "github.com/lonmouth/alien_wave/client/proto"
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
 type Client struct {
-	conn *grpc.ClientConn
+	conn   *grpc.ClientConn
 	client transmitter.TransmitterServiceClient
 }
 
-func NewClient(addr string) (*Client, error) {
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials())) // небезопасного соединения (без TLS)
+// opts дополняют параметры соединения, например подключение через bufconn в тестах
+func NewClient(addr string, opts ...grpc.DialOption) (*Client, error) {
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...) // небезопасного соединения (без TLS)
+	conn, err := grpc.NewClient(addr, opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Client{
-		conn:   conn, // соединение с gRPC-сервером
+		conn:   conn,                                          // соединение с gRPC-сервером
 		client: transmitter.NewTransmitterServiceClient(conn), // клиент для вызова методов gRPC-сервиса
 	}, nil
 }
 
-// метод для установления потокового соединения с gRPC-сервером
-func (c *Client) Stream() (transmitter.TransmitterService_StreamDataClient, error) {
-	return c.client.StreamData(context.Background(), &transmitter.Empty{}) // возвращает клиент для работы с потоком данных.
+// метод для установления потокового соединения с gRPC-сервером: точки данных и события сессий.
+// Отмена ctx закрывает поток
+func (c *Client) Stream(ctx context.Context) (transmitter.TransmitterService_StreamClient, error) {
+	return c.client.Stream(ctx, &transmitter.StreamRequest{}) // возвращает клиент для работы с потоком данных.
 }
 
 // метод для закрытия соединения с gRPC-сервером
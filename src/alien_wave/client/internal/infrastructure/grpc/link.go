@@ -0,0 +1,275 @@
+// github.com/lonmouth/alien_wave/client/internal/infrastructure/grpc/link.go
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	linkTimeoutFactor = 3                // сколько интервалов heartbeat без сообщений означают потерю соединения
+	minReconnectDelay = time.Second      // задержка перед первым переподключением
+	maxReconnectDelay = 30 * time.Second // предел задержки при повторяющихся ошибках
+)
+
+// ErrSubscription - подписка на чужую сессию не управляет скоростью отправки
+var ErrSubscription = errors.New("flow control is not available for a subscription")
+
+// Handler получает сообщения потока Connect или Subscribe; его реализует application.Detector
+type Handler interface {
+	StartSession(start *transmitter.SessionStart)
+	Process(point *transmitter.Transmission)
+	Heartbeat(hb *transmitter.Heartbeat)
+	EndSession(end *transmitter.SessionEnd)
+	LinkLost(silence time.Duration)
+}
+
+// состояние потока Connect
+type LinkState struct {
+	Connected    bool
+	Subscription string        // сессия, на которую подписан поток (пусто - собственный поток Connect)
+	SessionID    string        // текущая сессия (пусто - сессии нет)
+	Acked        uint64        // последняя подтвержденная точка сессии
+	Interval     time.Duration // запрошенный интервал между точками (0 - интервал сервера)
+	Paused       bool          // запрошена ли пауза
+	Reconnects   uint64        // количество переподключений
+}
+
+// Link - поток Connect с подтверждениями и управлением скоростью. После обрыва соединения Link
+// переподключается и продолжает сессию с последней подтвержденной точки, пока сервер ее хранит.
+// Подписка (Subscribe) получает сессию, которую отправляет сервер или другой клиент, без подтверждений
+type Link struct {
+	client       *Client
+	handler      Handler
+	subscription string // сессия подписки (пусто - поток Connect)
+
+	mu         sync.Mutex // защищает поля ниже и отправку в stream
+	connected  bool
+	stream     transmitter.TransmitterService_ConnectClient // nil для подписки
+	session    string
+	acked      uint64
+	interval   time.Duration
+	paused     bool
+	reconnects uint64
+}
+
+// создает поток Connect; сообщения передаются в h
+func (c *Client) Link(h Handler) *Link {
+	return &Link{client: c, handler: h}
+}
+
+// создает подписку на сессию sessionID; сообщения передаются в h. После обрыва соединения
+// подписка возобновляется, пока сессия существует на сервере
+func (c *Client) Subscribe(h Handler, sessionID string) *Link {
+	return &Link{client: c, handler: h, subscription: sessionID}
+}
+
+// Run читает поток до отмены ctx, переподключаясь после ошибок с растущей задержкой
+func (l *Link) Run(ctx context.Context) {
+	delay := minReconnectDelay
+	for {
+		received, err := l.connect(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if received { // соединение работало: следующая ошибка начинает задержки заново
+			delay = minReconnectDelay
+		}
+		log.Printf("Stream error: %v; reconnecting in %v", err, delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxReconnectDelay)
+
+		l.mu.Lock()
+		l.reconnects++
+		l.mu.Unlock()
+	}
+}
+
+// открывает поток и читает его до ошибки. received - было получено хотя бы одно сообщение
+func (l *Link) connect(ctx context.Context) (received bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := l.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		l.mu.Lock()
+		l.stream, l.connected = nil, false
+		l.mu.Unlock()
+	}()
+
+	var (
+		lastMessage atomic.Int64 // время получения последнего сообщения, наносекунды Unix
+		linkTimeout atomic.Int64 // через сколько тишины соединение считается потерянным (0 - сервер не отправляет heartbeat)
+	)
+	lastMessage.Store(time.Now().UnixNano())
+	go l.watch(ctx, cancel, &lastMessage, &linkTimeout)
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if status.Code(err) == codes.NotFound { // сессия истекла на сервере: следующее подключение начнет новую
+				l.mu.Lock()
+				l.session, l.acked = "", 0
+				l.mu.Unlock()
+			}
+			return received, err
+		}
+		received = true
+		lastMessage.Store(time.Now().UnixNano())
+
+		switch p := msg.Payload.(type) {
+		case *transmitter.StreamMessage_Start:
+			l.handler.StartSession(p.Start)
+			linkTimeout.Store(int64(linkTimeoutFactor * p.Start.HeartbeatInterval.AsDuration()))
+			l.mu.Lock()
+			if !p.Start.Resumed || l.session != p.Start.SessionId {
+				l.session, l.acked = p.Start.SessionId, 0
+			}
+			l.mu.Unlock()
+		case *transmitter.StreamMessage_Data:
+			l.handler.Process(p.Data)
+			l.ack(p.Data)
+		case *transmitter.StreamMessage_Heartbeat:
+			l.handler.Heartbeat(p.Heartbeat)
+		case *transmitter.StreamMessage_End:
+			l.handler.EndSession(p.End)
+			l.mu.Lock()
+			l.session, l.acked = "", 0
+			l.mu.Unlock()
+		}
+	}
+}
+
+// открывает поток подписки или Connect
+func (l *Link) dial(ctx context.Context) (interface {
+	Recv() (*transmitter.StreamMessage, error)
+}, error) {
+	if l.subscription == "" {
+		stream, err := l.client.client.Connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return stream, l.open(stream)
+	}
+	stream, err := l.client.client.Subscribe(ctx, &transmitter.SubscribeRequest{SessionId: l.subscription})
+	if err != nil {
+		return nil, err
+	}
+	l.mu.Lock()
+	l.connected = true
+	l.mu.Unlock()
+	log.Printf("Subscribed to session %s", l.subscription)
+	return stream, nil
+}
+
+// отправляет Open с текущей сессией и, если скорость менялась, Flow
+func (l *Link) open(stream transmitter.TransmitterService_ConnectClient) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	open := &transmitter.Open{SessionId: l.session, AfterSeq: l.acked}
+	if err := stream.Send(&transmitter.ClientMessage{Payload: &transmitter.ClientMessage_Open{Open: open}}); err != nil {
+		return err
+	}
+	if l.interval != 0 || l.paused {
+		if err := stream.Send(l.flow()); err != nil {
+			return err
+		}
+	}
+	if l.session != "" {
+		log.Printf("Resuming session %s after seq %d", l.session, l.acked)
+	}
+	l.stream, l.connected = stream, true
+	return nil
+}
+
+// подтверждает обработанную точку текущей сессии
+func (l *Link) ack(point *transmitter.Transmission) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if point.SessionId != l.session || point.Seq <= l.acked || l.stream == nil {
+		return
+	}
+	l.acked = point.Seq
+	ack := &transmitter.Ack{SessionId: point.SessionId, Seq: point.Seq}
+	if err := l.stream.Send(&transmitter.ClientMessage{Payload: &transmitter.ClientMessage_Ack{Ack: ack}}); err != nil {
+		log.Printf("Ack send error: %v", err) // обрыв обнаружит Recv; неподтвержденные точки сервер повторит
+	}
+}
+
+// SetFlow просит сервер изменить интервал между точками (0 - интервал сервера) или приостановить отправку.
+// Настройка сохраняется и повторяется после переподключения. Для подписки возвращает ErrSubscription
+func (l *Link) SetFlow(interval time.Duration, paused bool) error {
+	if l.subscription != "" {
+		return ErrSubscription
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.interval, l.paused = interval, paused
+	if l.stream == nil {
+		return nil
+	}
+	return l.stream.Send(l.flow())
+}
+
+func (l *Link) flow() *transmitter.ClientMessage {
+	flow := &transmitter.Flow{Paused: l.paused}
+	if l.interval != 0 {
+		flow.SendInterval = durationpb.New(l.interval)
+	}
+	return &transmitter.ClientMessage{Payload: &transmitter.ClientMessage_Flow{Flow: flow}}
+}
+
+// State возвращает текущее состояние потока
+func (l *Link) State() LinkState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return LinkState{
+		Connected:    l.connected,
+		Subscription: l.subscription,
+		SessionID:    l.session,
+		Acked:        l.acked,
+		Interval:     l.interval,
+		Paused:       l.paused,
+		Reconnects:   l.reconnects,
+	}
+}
+
+// watch отличает тишину источника от обрыва связи: пока сервер отправляет heartbeat, соединение живо.
+// Если сообщений нет дольше linkTimeout, потеря соединения передается в Handler и поток закрывается
+func (l *Link) watch(ctx context.Context, cancel context.CancelFunc, lastMessage, linkTimeout *atomic.Int64) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			timeout := time.Duration(linkTimeout.Load())
+			if silence := time.Since(time.Unix(0, lastMessage.Load())); timeout > 0 && silence > timeout {
+				l.handler.LinkLost(silence)
+				cancel()
+				return
+			}
+		}
+	}
+}
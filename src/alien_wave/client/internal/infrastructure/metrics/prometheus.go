@@ -0,0 +1,49 @@
+// github.com/lonmouth/alien_wave/client/internal/infrastructure/metrics/prometheus.go
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus экспортирует счетчики детектора. Метки сессий не используются,
+// чтобы количество рядов не росло с каждой новой сессией; значения по сессиям есть в admin API
+type Prometheus struct {
+	registry *prometheus.Registry
+	events   *prometheus.CounterVec
+	missing  prometheus.Counter
+}
+
+func New() *Prometheus {
+	p := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alien_wave_client_session_events_total",
+			Help: "События потока сессий по видам: gap, duplicate, reorder, session_start, session_resume, session_end, link_lost.",
+		}, []string{"kind"}),
+		missing: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alien_wave_client_missing_points_total",
+			Help: "Количество точек, пропущенных по номерам seq.",
+		}),
+	}
+	p.registry.MustRegister(p.events, p.missing,
+		prometheus.NewGoCollector(), prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
+	for _, kind := range []domain.SessionEventKind{domain.EventGap, domain.EventDuplicate, domain.EventReorder,
+		domain.EventSessionStart, domain.EventSessionResume, domain.EventSessionEnd, domain.EventLinkLost} {
+		p.events.WithLabelValues(string(kind)) // ряды видны со значением 0 до первого события
+	}
+	return p
+}
+
+func (p *Prometheus) SessionEvent(e domain.SessionEvent) {
+	p.events.WithLabelValues(string(e.Kind)).Inc()
+	p.missing.Add(float64(e.Count))
+}
+
+// обработчик /metrics
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{})
+}
@@ -2,6 +2,9 @@
 package postgres
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"log"
 	"time"
 
@@ -10,13 +13,24 @@ import (
 )
 
 type AnomalyModel struct {
-	ID           uint      `gorm:"primarykey"`
-	SessionID    string    `gorm:"column:session_id"`
-	Frequency    float64   `gorm:"column:frequency"`
-	Timestamp    time.Time `gorm:"column:timestamp"`
-	ExpectedMean float64   `gorm:"column:expected_mean"`
-	ExpectedSTD  float64   `gorm:"column:expected_std"`
-	K            float64   `gorm:"column:k"`
+	ID               uint      `gorm:"primarykey"`
+	SessionID        string    `gorm:"column:session_id"`
+	Channel          string    `gorm:"column:channel;size:64;not null;default:''"`
+	Frequency        float64   `gorm:"column:frequency"`
+	Timestamp        time.Time `gorm:"column:timestamp"`
+	ExpectedMean     float64   `gorm:"column:expected_mean"`
+	ExpectedSTD      float64   `gorm:"column:expected_std"`
+	K                float64   `gorm:"column:k"`
+	ConfigVersion    uint64    `gorm:"column:config_version"`
+	ZScore           float64   `gorm:"column:z_score"`
+	Direction        string    `gorm:"column:direction;size:8"`
+	Severity         uint8     `gorm:"column:severity;type:smallint;index"`
+	Algorithm        string    `gorm:"column:algorithm;size:32"`
+	AlgorithmVersion string    `gorm:"column:algorithm_version;size:16"`
+	IncidentID       *uint64   `gorm:"column:incident_id;index"`
+	Provisional      bool      `gorm:"column:provisional;not null;default:false"`
+
+	Incident *IncidentModel `gorm:"foreignKey:IncidentID;constraint:OnDelete:SET NULL"`
 }
 
 // Указываем явное имя таблицы
@@ -24,26 +38,163 @@ func (AnomalyModel) TableName() string {
 	return "anomalies"
 }
 
+type IncidentModel struct {
+	ID            uint64    `gorm:"primarykey"`
+	SessionID     string    `gorm:"column:session_id;index"`
+	StartedAt     time.Time `gorm:"column:started_at"`
+	EndedAt       time.Time `gorm:"column:ended_at"`
+	Points        uint      `gorm:"column:points"`
+	PeakFrequency float64   `gorm:"column:peak_frequency"`
+	PeakDeviation float64   `gorm:"column:peak_deviation"`
+	Direction     string    `gorm:"column:direction"`
+	Closed        bool      `gorm:"column:closed"`
+}
+
+func (IncidentModel) TableName() string {
+	return "incidents"
+}
+
+type ChangePointModel struct {
+	ID         uint64    `gorm:"primarykey"`
+	SessionID  string    `gorm:"column:session_id;index"`
+	Kind       string    `gorm:"column:kind;size:16"`
+	StartedAt  time.Time `gorm:"column:started_at"`
+	DetectedAt time.Time `gorm:"column:detected_at"`
+	Points     uint      `gorm:"column:points"`
+	Statistic  float64   `gorm:"column:statistic"`
+	BeforeMean float64   `gorm:"column:before_mean"`
+	BeforeSTD  float64   `gorm:"column:before_std"`
+	AfterMean  float64   `gorm:"column:after_mean"`
+	AfterSTD   float64   `gorm:"column:after_std"`
+	Retrained  bool      `gorm:"column:retrained"`
+}
+
+func (ChangePointModel) TableName() string {
+	return "change_points"
+}
+
+type SessionEventModel struct {
+	ID        uint64    `gorm:"primarykey"`
+	SessionID string    `gorm:"column:session_id;index"`
+	Kind      string    `gorm:"column:kind;size:16"`
+	At        time.Time `gorm:"column:at"`
+	Seq       uint64    `gorm:"column:seq"`
+	Expected  uint64    `gorm:"column:expected"`
+	Count     uint64    `gorm:"column:count"`
+	Detail    string    `gorm:"column:detail"`
+}
+
+func (SessionEventModel) TableName() string {
+	return "session_events"
+}
+
+// примененная конфигурация детектора; ее ID записывается в config_version аномалий.
+// Одинаковые параметры получают одну версию, в том числе после перезапуска клиента
+type ConfigVersionModel struct {
+	ID        uint64    `gorm:"primarykey"`
+	Hash      string    `gorm:"column:hash;size:64;uniqueIndex"` // SHA-256 параметров
+	Settings  string    `gorm:"column:settings;type:text"`       // параметры в JSON
+	CreatedAt time.Time `gorm:"column:created_at"`               // когда параметры были применены впервые
+}
+
+func (ConfigVersionModel) TableName() string {
+	return "config_versions"
+}
+
 type PostgresRepository struct {
 	db *gorm.DB // GORM — ORM (Object-Relational Mapping) для Go
 }
 
-func NewRepository(db *gorm.DB) domain.AnomalyRepository {
-	if err := db.AutoMigrate(&AnomalyModel{}); err != nil { // выполняет автоматическую миграцию схемы базы данных для модели AnomalyModel
+func NewRepository(db *gorm.DB, scale domain.SeverityScale) domain.AnomalyRepository {
+	if err := Migrate(db, scale); err != nil {
 		log.Fatalf("Migration failed: %v", err)
+	}
+	log.Println("Database migration completed successfully")
+	return &PostgresRepository{db: db}
 }
-log.Println("Database migration completed successfully")
+
+// создает репозиторий только для чтения, без миграции схемы
+func NewReader(db *gorm.DB) domain.AnomalyReader {
 	return &PostgresRepository{db: db}
 }
 
+// выполняет автоматическую миграцию схемы базы данных для моделей IncidentModel, AnomalyModel, ChangePointModel, SessionEventModel и ConfigVersionModel
+// и заполняет новые колонки у записей, сохраненных до их появления
+func Migrate(db *gorm.DB, scale domain.SeverityScale) error {
+	if err := db.AutoMigrate(&IncidentModel{}, &AnomalyModel{}, &ChangePointModel{}, &SessionEventModel{}, &ConfigVersionModel{}); err != nil {
+		return err
+	}
+	return backfillSeverity(db, scale)
+}
+
+// возвращает версию конфигурации с параметрами settings (JSON), при первом применении создает ее
+func ConfigVersion(db *gorm.DB, settings []byte) (uint64, error) {
+	sum := sha256.Sum256(settings)
+	m := ConfigVersionModel{Hash: hex.EncodeToString(sum[:])}
+	err := db.Where(ConfigVersionModel{Hash: m.Hash}).Attrs(ConfigVersionModel{Settings: string(settings)}).FirstOrCreate(&m).Error
+	if err != nil { // ту же версию мог одновременно создать другой клиент
+		err = db.Where(ConfigVersionModel{Hash: m.Hash}).First(&m).Error
+	}
+	if err != nil {
+		return 0, fmt.Errorf("config version: %w", err)
+	}
+	return m.ID, nil
+}
+
+// вычисляет z-оценку, направление и уровень важности для аномалий без алгоритма.
+// Все такие записи созданы первой версией детектора mean ± Kσ.
+// Повторный запуск не меняет уже заполненные записи
+func backfillSeverity(db *gorm.DB, scale domain.SeverityScale) error {
+	res := db.Exec(`
+		UPDATE anomalies SET
+			z_score = CASE
+				WHEN expected_std > 0 THEN (frequency - expected_mean) / expected_std
+				WHEN frequency < expected_mean THEN '-Infinity'::float8
+				ELSE 'Infinity'::float8
+			END,
+			direction = CASE WHEN frequency < expected_mean THEN ? ELSE ? END,
+			severity = CASE
+				WHEN expected_std = 0 OR ABS(frequency - expected_mean) >= ? * expected_std THEN ?
+				WHEN ABS(frequency - expected_mean) >= ? * expected_std THEN ?
+				ELSE ?
+			END,
+			algorithm = ?,
+			algorithm_version = ?
+		WHERE algorithm IS NULL OR algorithm = ''`,
+		string(domain.DirectionLow), string(domain.DirectionHigh),
+		scale.Critical, uint8(domain.SeverityCritical),
+		scale.Major, uint8(domain.SeverityMajor),
+		uint8(domain.SeverityMinor),
+		domain.ZScoreAlgorithm, "1",
+	)
+	if res.Error != nil {
+		return fmt.Errorf("backfill anomaly severity: %w", res.Error)
+	}
+	if res.RowsAffected > 0 {
+		log.Printf("Backfilled severity for %d anomalies", res.RowsAffected)
+	}
+	return nil
+}
+
 func (r *PostgresRepository) Save(a domain.Anomaly) error {
 	model := AnomalyModel{
-		SessionID:    a.SessionID,
-		Frequency:    a.Frequency,
-		Timestamp:    a.Timestamp,
-		ExpectedMean: a.ExpectedMean,
-		ExpectedSTD:  a.ExpectedSTD,
-		K:            a.K,
+		SessionID:        a.SessionID,
+		Channel:          a.Channel,
+		Frequency:        a.Frequency,
+		Timestamp:        a.Timestamp,
+		ExpectedMean:     a.ExpectedMean,
+		ExpectedSTD:      a.ExpectedSTD,
+		K:                a.K,
+		ConfigVersion:    a.ConfigVersion,
+		ZScore:           a.ZScore,
+		Direction:        string(a.Direction),
+		Severity:         uint8(a.Severity),
+		Algorithm:        a.Algorithm,
+		AlgorithmVersion: a.AlgorithmVersion,
+		Provisional:      a.Provisional,
+	}
+	if a.IncidentID != 0 {
+		model.IncidentID = &a.IncidentID
 	}
 
 	if err := r.db.Create(&model).Error; err != nil {
@@ -51,10 +202,288 @@ func (r *PostgresRepository) Save(a domain.Anomaly) error {
 		return err
 	}
 
-	log.Printf("✅ Anomaly saved | Session: %s | Freq: %.2f | Mean: %.2f | STD: %.2f",
-		a.SessionID, a.Frequency, a.ExpectedMean, a.ExpectedSTD)
+	log.Printf("✅ Anomaly saved | Session: %s | Channel: %s | Value: %.2f | Mean: %.2f | STD: %.2f | z: %.2f | Severity: %s",
+		a.SessionID, a.ChannelName(), a.Frequency, a.ExpectedMean, a.ExpectedSTD, a.ZScore, a.Severity)
+	return nil
+}
+
+func (r *PostgresRepository) Find(f domain.AnomalyFilter) ([]domain.Anomaly, error) {
+	q := r.db.Model(&AnomalyModel{})
+	if f.BySeverity {
+		q = q.Order("severity DESC")
+	}
+	q = q.Order("timestamp DESC")
+	if f.SessionID != "" {
+		q = q.Where("session_id = ?", f.SessionID)
+	}
+	if f.MinSeverity != domain.SeverityNone {
+		q = q.Where("severity >= ?", uint8(f.MinSeverity))
+	}
+	if f.Channel != "" {
+		q = q.Where("channel = ?", channelColumn(f.Channel))
+	}
+	if !f.From.IsZero() {
+		q = q.Where("timestamp >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		q = q.Where("timestamp <= ?", f.To)
+	}
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+
+	var models []AnomalyModel
+	if err := q.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	anomalies := make([]domain.Anomaly, 0, len(models))
+	for _, m := range models {
+		anomalies = append(anomalies, domain.Anomaly{
+			SessionID:        m.SessionID,
+			Channel:          m.Channel,
+			Frequency:        m.Frequency,
+			Timestamp:        m.Timestamp,
+			ExpectedMean:     m.ExpectedMean,
+			ExpectedSTD:      m.ExpectedSTD,
+			K:                m.K,
+			ConfigVersion:    m.ConfigVersion,
+			ZScore:           m.ZScore,
+			Direction:        domain.Direction(m.Direction),
+			Severity:         domain.Severity(m.Severity),
+			Algorithm:        m.Algorithm,
+			AlgorithmVersion: m.AlgorithmVersion,
+			IncidentID:       incidentID(m.IncidentID),
+			Provisional:      m.Provisional,
+		})
+	}
+	return anomalies, nil
+}
+
+// основной канал хранится пустой строкой, как у записей, сохраненных до появления колонки
+func channelColumn(name string) string {
+	if name == domain.FrequencyChannel {
+		return ""
+	}
+	return name
+}
+
+func incidentID(id *uint64) uint64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+func (r *PostgresRepository) SaveIncident(i *domain.Incident) error {
+	model := IncidentModel{
+		ID:            i.ID,
+		SessionID:     i.SessionID,
+		StartedAt:     i.StartedAt,
+		EndedAt:       i.EndedAt,
+		Points:        i.Points,
+		PeakFrequency: i.PeakFrequency,
+		PeakDeviation: i.PeakDeviation,
+		Direction:     string(i.Direction),
+		Closed:        i.Closed,
+	}
+	if err := r.db.Save(&model).Error; err != nil { // Save создает запись при нулевом ID, иначе обновляет
+		return err
+	}
+	i.ID = model.ID
+	return nil
+}
+
+// инциденты выбираются по тем же условиям, что и аномалии: время сравнивается с началом инцидента
+func (r *PostgresRepository) Incidents(f domain.AnomalyFilter) ([]domain.Incident, error) {
+	q := r.db.Model(&IncidentModel{})
+	if f.BySeverity {
+		q = q.Order("peak_deviation DESC")
+	}
+	q = q.Order("started_at DESC")
+	if f.SessionID != "" {
+		q = q.Where("session_id = ?", f.SessionID)
+	}
+	if !f.From.IsZero() {
+		q = q.Where("started_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		q = q.Where("started_at <= ?", f.To)
+	}
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+
+	var models []IncidentModel
+	if err := q.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	incidents := make([]domain.Incident, 0, len(models))
+	for _, m := range models {
+		incidents = append(incidents, domain.Incident{
+			ID:            m.ID,
+			SessionID:     m.SessionID,
+			StartedAt:     m.StartedAt,
+			EndedAt:       m.EndedAt,
+			Points:        m.Points,
+			PeakFrequency: m.PeakFrequency,
+			PeakDeviation: m.PeakDeviation,
+			Direction:     domain.Direction(m.Direction),
+			Closed:        m.Closed,
+		})
+	}
+	return incidents, nil
+}
+
+func (r *PostgresRepository) Sessions() ([]domain.SessionSummary, error) {
+	var rows []struct {
+		SessionID    string
+		Anomalies    uint
+		FirstSeen    time.Time
+		LastSeen     time.Time
+		MinFrequency float64
+		MaxFrequency float64
+		ExpectedMean float64
+		ExpectedSTD  float64 `gorm:"column:expected_std"`
+	}
+	err := r.db.Model(&AnomalyModel{}).
+		Select(`session_id,
+			COUNT(*) AS anomalies,
+			MIN(timestamp) AS first_seen,
+			MAX(timestamp) AS last_seen,
+			MIN(frequency) AS min_frequency,
+			MAX(frequency) AS max_frequency,
+			AVG(expected_mean) AS expected_mean,
+			AVG(expected_std) AS expected_std`).
+		Group("session_id").
+		Order("last_seen DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]domain.SessionSummary, 0, len(rows))
+	for _, row := range rows {
+		summaries = append(summaries, domain.SessionSummary(row))
+	}
+	return summaries, nil
+}
+
+func (r *PostgresRepository) SaveChangePoint(cp *domain.ChangePoint) error {
+	model := ChangePointModel{
+		SessionID:  cp.SessionID,
+		Kind:       string(cp.Kind),
+		StartedAt:  cp.StartedAt,
+		DetectedAt: cp.DetectedAt,
+		Points:     cp.Points,
+		Statistic:  cp.Statistic,
+		BeforeMean: cp.BeforeMean,
+		BeforeSTD:  cp.BeforeSTD,
+		AfterMean:  cp.AfterMean,
+		AfterSTD:   cp.AfterSTD,
+		Retrained:  cp.Retrained,
+	}
+	if err := r.db.Create(&model).Error; err != nil {
+		return err
+	}
+	cp.ID = model.ID
 	return nil
 }
 
+// точки изменения выбираются по тем же условиям, что и аномалии: время сравнивается с моментом обнаружения
+func (r *PostgresRepository) ChangePoints(f domain.AnomalyFilter) ([]domain.ChangePoint, error) {
+	q := r.db.Model(&ChangePointModel{}).Order("detected_at DESC")
+	if f.SessionID != "" {
+		q = q.Where("session_id = ?", f.SessionID)
+	}
+	if !f.From.IsZero() {
+		q = q.Where("detected_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		q = q.Where("detected_at <= ?", f.To)
+	}
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+
+	var models []ChangePointModel
+	if err := q.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	points := make([]domain.ChangePoint, 0, len(models))
+	for _, m := range models {
+		points = append(points, domain.ChangePoint{
+			ID:         m.ID,
+			SessionID:  m.SessionID,
+			Kind:       domain.ChangeKind(m.Kind),
+			StartedAt:  m.StartedAt,
+			DetectedAt: m.DetectedAt,
+			Points:     m.Points,
+			Statistic:  m.Statistic,
+			BeforeMean: m.BeforeMean,
+			BeforeSTD:  m.BeforeSTD,
+			AfterMean:  m.AfterMean,
+			AfterSTD:   m.AfterSTD,
+			Retrained:  m.Retrained,
+		})
+	}
+	return points, nil
+}
+
+func (r *PostgresRepository) SaveSessionEvent(e *domain.SessionEvent) error {
+	model := SessionEventModel{
+		SessionID: e.SessionID,
+		Kind:      string(e.Kind),
+		At:        e.At,
+		Seq:       e.Seq,
+		Expected:  e.Expected,
+		Count:     e.Count,
+		Detail:    e.Detail,
+	}
+	if err := r.db.Create(&model).Error; err != nil {
+		return err
+	}
+	e.ID = model.ID
+	return nil
+}
+
+func (r *PostgresRepository) SessionEvents(f domain.AnomalyFilter) ([]domain.SessionEvent, error) {
+	q := r.db.Model(&SessionEventModel{}).Order("at DESC")
+	if f.SessionID != "" {
+		q = q.Where("session_id = ?", f.SessionID)
+	}
+	if !f.From.IsZero() {
+		q = q.Where("at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		q = q.Where("at <= ?", f.To)
+	}
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+
+	var models []SessionEventModel
+	if err := q.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.SessionEvent, 0, len(models))
+	for _, m := range models {
+		events = append(events, domain.SessionEvent{
+			ID:        m.ID,
+			SessionID: m.SessionID,
+			Kind:      domain.SessionEventKind(m.Kind),
+			At:        m.At,
+			Seq:       m.Seq,
+			Expected:  m.Expected,
+			Count:     m.Count,
+			Detail:    m.Detail,
+		})
+	}
+	return events, nil
+}
+
 // psql -h localhost -U postgres -c "DROP DATABASE IF EXISTS dmitrii;"
-// psql -h localhost -U postgres -c "CREATE DATABASE dmitrii OWNER dmitrii;"
\ No newline at end of file
+// psql -h localhost -U postgres -c "CREATE DATABASE dmitrii OWNER dmitrii;"
@@ -0,0 +1,393 @@
+// github.com/lonmouth/alien_wave/client/internal/infrastructure/webhook/notifier.go
+package webhook
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+)
+
+// размер очереди входящих аномалий; при переполнении новые аномалии отбрасываются
+const queueSize = 1024
+
+// настройки отправки уведомлений, могут меняться на лету через Update
+type Settings struct {
+	URLs       []string      // адреса, на которые отправляются уведомления
+	Secret     string        // ключ HMAC-SHA256 для подписи тела запроса (пусто - без подписи)
+	Template   string        // text/template, формирующий JSON-тело (пусто - шаблон по умолчанию)
+	Timeout    time.Duration // таймаут одного HTTP-запроса
+	MaxRetries int           // количество повторов после неудачной попытки
+	Backoff    time.Duration // задержка перед первым повтором, далее удваивается
+	RatePerMin float64       // сколько уведомлений в минуту допускается на сессию (0 - без ограничений)
+	Burst      int           // сколько уведомлений сессии можно отправить подряд
+	GroupMax   time.Duration // сколько открытый инцидент копит аномалии до отправки (0 - до завершения детектором)
+}
+
+// Notifier собирает аномалии по инцидентам детектора (Anomaly.IncidentID) и отправляет инцидент
+// POST-запросом на webhook, когда детектор его завершает
+type Notifier struct {
+	queue   chan queued
+	alerts  chan domain.Alert
+	changes chan domain.ChangePoint
+	sendq   chan []byte // готовые тела запросов
+	stop    chan struct{}
+	closed  atomic.Bool // после Close уведомления не принимаются
+	wg      sync.WaitGroup
+
+	mu       sync.Mutex // защищает settings, tmpl и sender
+	settings Settings
+	tmpl     *template.Template
+	sender   *sender
+
+	groups  map[groupKey]*incident // открытые инциденты
+	buckets map[string]*bucket     // ограничители частоты по сессиям
+}
+
+// элемент очереди группировки: аномалия или завершение инцидента. Общая очередь
+// сохраняет порядок: завершение не обгоняет аномалии своего инцидента
+type queued struct {
+	anomaly domain.Anomaly
+	end     *domain.Incident // не nil - инцидент завершен детектором
+}
+
+// инцидент детектора; ID 0 - инцидент, который детектору не удалось сохранить
+type groupKey struct {
+	session  string
+	incident uint64
+}
+
+func New(s Settings) (*Notifier, error) {
+	n := &Notifier{
+		queue:   make(chan queued, queueSize),
+		alerts:  make(chan domain.Alert, queueSize),
+		changes: make(chan domain.ChangePoint, queueSize),
+		sendq:   make(chan []byte, queueSize),
+		stop:    make(chan struct{}),
+		groups:  make(map[groupKey]*incident),
+		buckets: make(map[string]*bucket),
+	}
+	if err := n.Update(s); err != nil {
+		return nil, err
+	}
+
+	n.wg.Add(2)
+	go n.group()
+	go n.deliver()
+	return n, nil
+}
+
+// атомарно применяет новые настройки; при ошибке в шаблоне действующие настройки не меняются.
+// Уже сформированные инциденты отправляются по новым адресам
+func (n *Notifier) Update(s Settings) error {
+	tmpl, err := parseTemplate(s.Template)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.settings = s
+	n.tmpl = tmpl
+	n.sender = newSender(s)
+	return nil
+}
+
+func (n *Notifier) current() (Settings, *template.Template, *sender) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.settings, n.tmpl, n.sender
+}
+
+// ставит аномалию в очередь на отправку, не блокируя вызывающего
+func (n *Notifier) Notify(a domain.Anomaly) {
+	if n.closed.Load() {
+		log.Printf("Webhook notifier is closed, anomaly of session %s dropped", a.SessionID)
+		return
+	}
+	select {
+	case n.queue <- queued{anomaly: a}:
+	default:
+		log.Printf("Webhook queue is full, anomaly of session %s dropped", a.SessionID)
+	}
+}
+
+// ставит в очередь завершение инцидента детектора: собранные аномалии инцидента отправляются одним уведомлением
+func (n *Notifier) NotifyIncidentClosed(inc domain.Incident) {
+	if n.closed.Load() { // открытые инциденты уже отправлены при закрытии
+		return
+	}
+	select {
+	case n.queue <- queued{end: &inc}:
+	default:
+		log.Printf("Webhook queue is full, end of incident %d of session %s dropped", inc.ID, inc.SessionID)
+	}
+}
+
+// ставит уведомление правила алертинга в очередь. Алерты не группируются и не ограничиваются по частоте:
+// это уже сделано правилами
+func (n *Notifier) NotifyAlert(a domain.Alert) {
+	if n.closed.Load() {
+		log.Printf("Webhook notifier is closed, alert %s of session %s dropped", a.Rule, a.SessionID)
+		return
+	}
+	select {
+	case n.alerts <- a:
+	default:
+		log.Printf("Webhook queue is full, alert %s of session %s dropped", a.Rule, a.SessionID)
+	}
+}
+
+// ставит уведомление об изменении распределения в очередь; такие события редки и отправляются без группировки
+func (n *Notifier) NotifyChangePoint(cp domain.ChangePoint) {
+	if n.closed.Load() {
+		log.Printf("Webhook notifier is closed, change point of session %s dropped", cp.SessionID)
+		return
+	}
+	select {
+	case n.changes <- cp:
+	default:
+		log.Printf("Webhook queue is full, change point of session %s dropped", cp.SessionID)
+	}
+}
+
+// останавливает прием, отправляет открытые инциденты и ждет завершения доставки или отмены ctx.
+// Повторный вызов только ждет завершения доставки
+func (n *Notifier) Close(ctx context.Context) error {
+	if n.closed.CompareAndSwap(false, true) {
+		close(n.stop)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.Join(errors.New("webhook notifier: pending deliveries dropped"), ctx.Err())
+	}
+}
+
+// инцидент - аномалии одного инцидента детектора
+type incident struct {
+	IncidentID    uint64 // ID инцидента детектора (0 - не сохранен)
+	SessionID     string
+	FirstSeen     time.Time
+	LastSeen      time.Time
+	Count         int            // количество аномалий в инциденте
+	Suppressed    int            // сколько инцидентов сессии не отправлено из-за ограничения частоты
+	Peak          domain.Anomaly // аномалия с наибольшим отклонением
+	PeakDeviation float64        // отклонение пиковой аномалии в стандартных отклонениях
+	ConfigVersion uint64         // версия конфигурации последней аномалии
+
+	openedAt time.Time // локальное время первой аномалии
+}
+
+// горутина группировки: собирает аномалии по инцидентам, отправляет их при завершении детектором
+// или по GroupMax и передает готовые тела запросов на доставку
+func (n *Notifier) group() {
+	defer n.wg.Done()
+	defer close(n.sendq)
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case q := <-n.queue:
+			n.receive(q, time.Now())
+		case a := <-n.alerts:
+			n.alert(a)
+		case cp := <-n.changes:
+			n.changePoint(cp)
+		case now := <-ticker.C:
+			n.flush(now, false)
+		case <-n.stop:
+		drain: // дочитываем очередь перед завершением
+			for {
+				select {
+				case q := <-n.queue:
+					n.receive(q, time.Now())
+				case a := <-n.alerts:
+					n.alert(a)
+				case cp := <-n.changes:
+					n.changePoint(cp)
+				default:
+					break drain
+				}
+			}
+			n.flush(time.Now(), true)
+			return
+		}
+	}
+}
+
+func (n *Notifier) receive(q queued, now time.Time) {
+	if q.end != nil {
+		n.end(*q.end, now)
+	} else {
+		n.add(q.anomaly, now)
+	}
+}
+
+func (n *Notifier) add(a domain.Anomaly, now time.Time) {
+	settings, _, _ := n.current()
+	if len(settings.URLs) == 0 {
+		return
+	}
+
+	key := groupKey{a.SessionID, a.IncidentID}
+	inc, ok := n.groups[key]
+	if !ok {
+		inc = &incident{IncidentID: a.IncidentID, SessionID: a.SessionID, FirstSeen: a.Timestamp, Peak: a, PeakDeviation: domain.Deviation(a), openedAt: now}
+		n.groups[key] = inc
+	}
+	inc.Count++
+	inc.LastSeen = a.Timestamp
+	inc.ConfigVersion = a.ConfigVersion
+	if dev := domain.Deviation(a); dev > inc.PeakDeviation {
+		inc.Peak, inc.PeakDeviation = a, dev
+	}
+}
+
+// отправляет аномалии инцидента, завершенного детектором. Аномалии, сохраненные до того,
+// как инцидент получил ID, тоже относятся к нему
+func (n *Notifier) end(done domain.Incident, now time.Time) {
+	settings, _, _ := n.current()
+	for _, id := range []uint64{0, done.ID} {
+		if inc, ok := n.groups[groupKey{done.SessionID, id}]; ok {
+			n.close(inc, now, settings)
+		}
+	}
+}
+
+// отправляет инциденты, которые открыты дольше GroupMax (all - все открытые),
+// и удаляет ограничители частоты сессий без открытых инцидентов
+func (n *Notifier) flush(now time.Time, all bool) {
+	settings, _, _ := n.current()
+	open := make(map[string]bool, len(n.groups))
+	for _, inc := range n.groups {
+		if all || (settings.GroupMax > 0 && now.Sub(inc.openedAt) >= settings.GroupMax) {
+			n.close(inc, now, settings)
+		} else {
+			open[inc.SessionID] = true
+		}
+	}
+
+	burst := float64(max(settings.Burst, 1))
+	for id, b := range n.buckets {
+		if open[id] || !b.full(now, settings.RatePerMin/60, burst) {
+			continue
+		}
+		// заполненное ведро не отличается от нового; без удаления карта росла бы с каждой сессией
+		if b.suppressed > 0 {
+			log.Printf("Webhook rate limit: %d suppressed incidents of idle session %s were not reported", b.suppressed, id)
+		}
+		delete(n.buckets, id)
+	}
+}
+
+// передает инцидент на доставку с учетом ограничения частоты по сессии
+func (n *Notifier) close(inc *incident, now time.Time, settings Settings) {
+	delete(n.groups, groupKey{inc.SessionID, inc.IncidentID})
+
+	b, ok := n.buckets[inc.SessionID]
+	if !ok {
+		b = &bucket{tokens: float64(max(settings.Burst, 1)), last: now}
+		n.buckets[inc.SessionID] = b
+	}
+	if settings.RatePerMin > 0 && !b.take(now, settings.RatePerMin/60, float64(max(settings.Burst, 1))) {
+		b.suppressed++
+		log.Printf("Webhook rate limit: incident of session %s suppressed (%d anomalies)", inc.SessionID, inc.Count)
+		return
+	}
+	inc.Suppressed, b.suppressed = b.suppressed, 0
+
+	_, tmpl, _ := n.current()
+	body, err := render(tmpl, *inc)
+	if err != nil {
+		log.Printf("Webhook payload error: %v", err)
+		return
+	}
+	n.enqueue(body)
+}
+
+func (n *Notifier) alert(a domain.Alert) {
+	settings, _, _ := n.current()
+	if len(settings.URLs) == 0 {
+		return
+	}
+	body, err := renderAlert(a)
+	if err != nil {
+		log.Printf("Webhook payload error: %v", err)
+		return
+	}
+	n.enqueue(body)
+}
+
+func (n *Notifier) changePoint(cp domain.ChangePoint) {
+	settings, _, _ := n.current()
+	if len(settings.URLs) == 0 {
+		return
+	}
+	body, err := renderChangePoint(cp)
+	if err != nil {
+		log.Printf("Webhook payload error: %v", err)
+		return
+	}
+	n.enqueue(body)
+}
+
+func (n *Notifier) enqueue(body []byte) {
+	select {
+	case n.sendq <- body:
+	default:
+		log.Println("Webhook delivery queue is full, notification dropped")
+	}
+}
+
+// ограничитель частоты "ведро с токенами"
+type bucket struct {
+	tokens     float64
+	last       time.Time
+	suppressed int // сколько инцидентов отброшено с момента последней отправки
+}
+
+// накопилось ли в ведре снова burst токенов; без ограничения частоты ведро всегда полное
+func (b *bucket) full(now time.Time, perSecond, burst float64) bool {
+	return perSecond <= 0 || b.tokens+now.Sub(b.last).Seconds()*perSecond >= burst
+}
+
+func (b *bucket) take(now time.Time, perSecond, burst float64) bool {
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// горутина доставки: отправляет инциденты по очереди, не блокируя группировку
+func (n *Notifier) deliver() {
+	defer n.wg.Done()
+
+	for body := range n.sendq {
+		settings, _, snd := n.current()
+		for _, url := range settings.URLs {
+			if err := snd.post(n.stop, url, body); err != nil {
+				log.Printf("Webhook delivery to %s failed: %v", url, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// github.com/lonmouth/alien_wave/client/internal/infrastructure/webhook/notifier_test.go
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+)
+
+// запускает Notifier; t.Cleanup закрывает его, дожидаясь доставки
+func newNotifier(t *testing.T, s Settings) *Notifier {
+	t.Helper()
+	if s.Timeout == 0 {
+		s.Timeout = time.Second
+	}
+	n, err := New(s)
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	t.Cleanup(func() { closeNotifier(t, n) })
+	return n
+}
+
+// закрывает Notifier, дожидаясь доставки; повторное закрытие допустимо
+func closeNotifier(t *testing.T, n *Notifier) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := n.Close(ctx); err != nil {
+		t.Errorf("close notifier: %v", err)
+	}
+}
+
+// аномалия инцидента детектора 1 сессии session
+func anomaly(session string, z float64, at time.Time) domain.Anomaly {
+	return domain.Anomaly{
+		SessionID:    session,
+		IncidentID:   1,
+		Frequency:    10 + z,
+		Timestamp:    at,
+		ExpectedMean: 10,
+		ExpectedSTD:  1,
+		K:            3,
+		ZScore:       z,
+		Direction:    domain.DirectionOf(10+z, 10),
+	}
+}
+
+// тело уведомления об инциденте в шаблоне по умолчанию
+type incidentBody struct {
+	Event      string `json:"event"`
+	IncidentID uint64 `json:"incident_id"`
+	SessionID  string `json:"session_id"`
+	Count      int    `json:"count"`
+	Peak       struct {
+		Frequency *float64 `json:"frequency"`
+		Deviation *float64 `json:"deviation"`
+		ZScore    *float64 `json:"z_score"`
+	} `json:"peak"`
+}
+
+func decode(t *testing.T, body []byte) incidentBody {
+	t.Helper()
+	var b incidentBody
+	if err := json.Unmarshal(body, &b); err != nil {
+		t.Fatalf("decode %s: %v", body, err)
+	}
+	return b
+}
+
+func TestRateLimitSuppressesFlood(t *testing.T) {
+	r := newReceiver(t, always(http.StatusOK))
+	// каждая аномалия - отдельный инцидент детектора; сессии разрешено 2 подряд и 1 в минуту
+	n := newNotifier(t, Settings{URLs: []string{r.URL}, RatePerMin: 1, Burst: 2})
+
+	now := time.Now()
+	for i := range 20 {
+		a := anomaly("flood", 4, now.Add(time.Duration(i)*time.Millisecond))
+		a.IncidentID = uint64(i + 1)
+		n.Notify(a)
+	}
+	n.Notify(anomaly("other", 4, now)) // у другой сессии свой ограничитель
+	closeNotifier(t, n)
+
+	sessions := map[string]int{}
+	for _, req := range r.all() {
+		sessions[decode(t, req.body).SessionID]++
+	}
+	if sessions["flood"] != 2 || sessions["other"] != 1 {
+		t.Errorf("delivered notifications by session = %v, want flood:2 other:1", sessions)
+	}
+}
+
+func TestBurstIsGroupedIntoOneIncident(t *testing.T) {
+	r := newReceiver(t, always(http.StatusOK))
+	n := newNotifier(t, Settings{URLs: []string{r.URL}})
+
+	now := time.Now()
+	for i, z := range []float64{3.5, -6, 4, 3.2, 5} {
+		n.Notify(anomaly("burst", z, now.Add(time.Duration(i)*time.Millisecond)))
+	}
+	next := anomaly("burst", 4, now.Add(time.Second)) // следующий инцидент той же сессии
+	next.IncidentID = 2
+	n.Notify(next)
+
+	// инцидент отправляется, когда его завершает детектор, без остановки Notifier
+	n.NotifyIncidentClosed(domain.Incident{ID: 1, SessionID: "burst", Closed: true})
+	var got request
+	select {
+	case got = <-r.got:
+	case <-time.After(5 * time.Second):
+		t.Fatal("incident was not delivered after the detector closed it")
+	}
+	b := decode(t, got.body)
+	if b.Event != "anomaly_incident" || b.IncidentID != 1 || b.SessionID != "burst" || b.Count != 5 {
+		t.Errorf("incident = %+v, want anomaly_incident 1 of session burst with 5 anomalies", b)
+	}
+	if b.Peak.ZScore == nil || *b.Peak.ZScore != -6 || b.Peak.Deviation == nil || *b.Peak.Deviation != 6 {
+		t.Errorf("peak = z %v, deviation %v; want z -6, deviation 6", b.Peak.ZScore, b.Peak.Deviation)
+	}
+
+	closeNotifier(t, n) // открытый инцидент 2 отправляется при закрытии
+	if count := r.count(); count != 2 {
+		t.Errorf("delivered %d notifications for two incidents, want 2", count)
+	}
+}
+
+func TestLongIncidentIsSentAfterGroupMax(t *testing.T) {
+	r := newReceiver(t, always(http.StatusOK))
+	n := newNotifier(t, Settings{URLs: []string{r.URL}, GroupMax: 100 * time.Millisecond})
+
+	n.Notify(anomaly("long", 4, time.Now()))
+	select {
+	case got := <-r.got:
+		if b := decode(t, got.body); b.IncidentID != 1 || b.Count != 1 {
+			t.Errorf("incident = %+v, want incident 1 with 1 anomaly", b)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("open incident was not delivered after GroupMax")
+	}
+}
+
+func TestCloseTwice(t *testing.T) {
+	r := newReceiver(t, always(http.StatusOK))
+	n := newNotifier(t, Settings{URLs: []string{r.URL}})
+
+	n.Notify(anomaly("twice", 4, time.Now()))
+	closeNotifier(t, n)
+	closeNotifier(t, n)
+	n.Notify(anomaly("twice", 5, time.Now())) // после закрытия отбрасывается
+
+	if count := r.count(); count != 1 {
+		t.Errorf("delivered %d notifications, want 1 flushed on close", count)
+	}
+}
@@ -0,0 +1,141 @@
+// github.com/lonmouth/alien_wave/client/internal/infrastructure/webhook/payload.go
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"text/template"
+	"time"
+
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+)
+
+// шаблон тела уведомления по умолчанию. В шаблоне доступны поля incident и функция json,
+// которая экранирует значение как JSON
+const DefaultTemplate = `{
+  "event": "anomaly_incident",
+  "incident_id": {{.IncidentID}},
+  "session_id": {{json .SessionID}},
+  "first_seen": {{json .FirstSeen}},
+  "last_seen": {{json .LastSeen}},
+  "count": {{.Count}},
+  "suppressed": {{.Suppressed}},
+  "peak": {
+    "channel": {{json .Peak.ChannelName}},
+    "frequency": {{json .Peak.Frequency}},
+    "timestamp": {{json .Peak.Timestamp}},
+    "expected_mean": {{json .Peak.ExpectedMean}},
+    "expected_std": {{json .Peak.ExpectedSTD}},
+    "k": {{json .Peak.K}},
+    "deviation": {{json .PeakDeviation}},
+    "z_score": {{json .Peak.ZScore}},
+    "direction": {{json .Peak.Direction}},
+    "severity": {{json .Peak.Severity}},
+    "algorithm": {{json .Peak.Algorithm}},
+    "algorithm_version": {{json .Peak.AlgorithmVersion}}
+  },
+  "config_version": {{.ConfigVersion}}
+}`
+
+var funcs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		if f, ok := v.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+			return "null", nil // JSON не поддерживает бесконечность
+		}
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := template.New("webhook").Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse webhook template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// формирует тело запроса и проверяет, что результат - корректный JSON
+func render(tmpl *template.Template, inc incident) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, inc); err != nil {
+		return nil, fmt.Errorf("render webhook template: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("webhook template produced invalid JSON: %s", buf.String())
+	}
+	return buf.Bytes(), nil
+}
+
+// тело уведомления правила алертинга
+type alertPayload struct {
+	Event     string     `json:"event"`
+	Rule      string     `json:"rule"`
+	Severity  string     `json:"severity"`
+	State     string     `json:"state"`
+	SessionID string     `json:"session_id"`
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	Value     *float64   `json:"value"` // null, если значение бесконечно
+	Summary   string     `json:"summary"`
+}
+
+func renderAlert(a domain.Alert) ([]byte, error) {
+	p := alertPayload{
+		Event:     "alert",
+		Rule:      a.Rule,
+		Severity:  a.Severity,
+		State:     string(a.State),
+		SessionID: a.SessionID,
+		StartsAt:  a.StartsAt,
+		Summary:   a.Summary,
+	}
+	if !a.EndsAt.IsZero() {
+		p.EndsAt = &a.EndsAt
+	}
+	if !math.IsInf(a.Value, 0) && !math.IsNaN(a.Value) {
+		p.Value = &a.Value
+	}
+	return json.Marshal(p)
+}
+
+// тело уведомления об изменении распределения сессии
+type changePointPayload struct {
+	Event      string    `json:"event"`
+	ID         uint64    `json:"id"`
+	SessionID  string    `json:"session_id"`
+	Kind       string    `json:"kind"`
+	StartedAt  time.Time `json:"started_at"`
+	DetectedAt time.Time `json:"detected_at"`
+	Points     uint      `json:"points"`
+	Statistic  float64   `json:"statistic"`
+	Before     estimate  `json:"before"`
+	After      estimate  `json:"after"`
+	Retrained  bool      `json:"retrained"`
+}
+
+type estimate struct {
+	Mean float64 `json:"mean"`
+	STD  float64 `json:"std"`
+}
+
+func renderChangePoint(cp domain.ChangePoint) ([]byte, error) {
+	return json.Marshal(changePointPayload{
+		Event:      "change_point",
+		ID:         cp.ID,
+		SessionID:  cp.SessionID,
+		Kind:       string(cp.Kind),
+		StartedAt:  cp.StartedAt,
+		DetectedAt: cp.DetectedAt,
+		Points:     cp.Points,
+		Statistic:  cp.Statistic,
+		Before:     estimate{Mean: cp.BeforeMean, STD: cp.BeforeSTD},
+		After:      estimate{Mean: cp.AfterMean, STD: cp.AfterSTD},
+		Retrained:  cp.Retrained,
+	})
+}
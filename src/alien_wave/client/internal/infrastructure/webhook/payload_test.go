@@ -0,0 +1,50 @@
+// github.com/lonmouth/alien_wave/client/internal/infrastructure/webhook/payload_test.go
+package webhook
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/lonmouth/alien_wave/client/internal/domain"
+)
+
+func TestInfiniteScoresAreNull(t *testing.T) {
+	for _, inf := range []float64{math.Inf(1), math.Inf(-1)} {
+		r := newReceiver(t, always(http.StatusOK))
+		n := newNotifier(t, Settings{URLs: []string{r.URL}})
+
+		a := anomaly("inf", 0, time.Now())
+		a.ZScore, a.ExpectedSTD = inf, 0 // при нулевом σ отклонение бесконечно
+		n.Notify(a)
+		closeNotifier(t, n)
+
+		reqs := r.all()
+		if len(reqs) != 1 {
+			t.Fatalf("z=%v: delivered %d notifications, want 1", inf, len(reqs))
+		}
+		b := decode(t, reqs[0].body)
+		if b.Peak.ZScore != nil || b.Peak.Deviation != nil {
+			t.Errorf("z=%v: z_score %v, deviation %v; want null", inf, b.Peak.ZScore, b.Peak.Deviation)
+		}
+		if b.Peak.Frequency == nil || *b.Peak.Frequency != 10 {
+			t.Errorf("z=%v: finite frequency = %v, want 10", inf, b.Peak.Frequency)
+		}
+	}
+}
+
+func TestInfiniteAlertValueIsNull(t *testing.T) {
+	body, err := renderAlert(domain.Alert{Rule: "z", SessionID: "inf", Value: math.Inf(-1), StartsAt: time.Now()})
+	if err != nil {
+		t.Fatalf("render alert: %v", err)
+	}
+	var p map[string]any
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatalf("decode %s: %v", body, err)
+	}
+	if v, ok := p["value"]; !ok || v != nil {
+		t.Errorf("value = %v (present %t), want null", v, ok)
+	}
+}
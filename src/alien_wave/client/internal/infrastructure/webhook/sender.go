@@ -0,0 +1,99 @@
+// github.com/lonmouth/alien_wave/client/internal/infrastructure/webhook/sender.go
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// заголовки подписи: получатель вычисляет HMAC-SHA256(secret, timestamp + "." + body)
+// и сравнивает с SignatureHeader, а по TimestampHeader отбрасывает старые запросы
+const (
+	SignatureHeader = "X-Alien-Wave-Signature"
+	TimestampHeader = "X-Alien-Wave-Timestamp"
+)
+
+// максимальная задержка между повторами
+const maxBackoff = 30 * time.Second
+
+type sender struct {
+	client     *http.Client
+	secret     []byte
+	maxRetries int
+	backoff    time.Duration
+}
+
+func newSender(s Settings) *sender {
+	return &sender{
+		client:     &http.Client{Timeout: s.Timeout},
+		secret:     []byte(s.Secret),
+		maxRetries: s.MaxRetries,
+		backoff:    s.Backoff,
+	}
+}
+
+// отправляет тело на url, повторяя попытку при сетевых ошибках, 429 и 5xx с экспоненциальной задержкой.
+// После закрытия stop повторы не выполняются
+func (s *sender) post(stop <-chan struct{}, url string, body []byte) error {
+	delay := s.backoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		if retry, err = s.try(url, body); err == nil || !retry || attempt >= s.maxRetries {
+			return err
+		}
+
+		select {
+		case <-stop:
+			return fmt.Errorf("%w (retries cancelled on shutdown)", err)
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxBackoff)
+	}
+}
+
+// одна попытка доставки; retry сообщает, имеет ли смысл повторять
+func (s *sender) try(url string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(s.secret) > 0 {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, ts)
+		req.Header.Set(SignatureHeader, "sha256="+Sign(s.secret, ts, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body) // позволяет переиспользовать соединение
+
+	switch {
+	case resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("unexpected status %s", resp.Status)
+	default:
+		return false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+}
+
+// вычисляет подпись запроса в шестнадцатеричном виде
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,146 @@
+// github.com/lonmouth/alien_wave/client/internal/infrastructure/webhook/sender_test.go
+package webhook
+
+import (
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// запрос, полученный тестовым получателем
+type request struct {
+	header http.Header
+	body   []byte
+	at     time.Time
+}
+
+// получатель webhook: отвечает статусом status(номер попытки с 1) и сохраняет запросы
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []request
+	got      chan request
+}
+
+func newReceiver(t *testing.T, status func(attempt int) int) *receiver {
+	r := &receiver{got: make(chan request, 100)}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		got := request{header: req.Header.Clone(), body: body, at: time.Now()}
+		r.mu.Lock()
+		r.requests = append(r.requests, got)
+		attempt := len(r.requests)
+		r.mu.Unlock()
+		w.WriteHeader(status(attempt))
+		r.got <- got
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+func (r *receiver) all() []request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]request(nil), r.requests...)
+}
+
+func always(code int) func(int) int { return func(int) int { return code } }
+
+func TestSignatureMatchesSign(t *testing.T) {
+	r := newReceiver(t, always(http.StatusOK))
+	s := newSender(Settings{Secret: "s3cret", Timeout: time.Second})
+	body := []byte(`{"event":"test"}`)
+
+	if err := s.post(make(chan struct{}), r.URL, body); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	got := <-r.got
+	ts := got.header.Get(TimestampHeader)
+	if ts == "" {
+		t.Fatalf("%s header is missing", TimestampHeader)
+	}
+	want := "sha256=" + Sign([]byte("s3cret"), ts, got.body)
+	if sig := got.header.Get(SignatureHeader); !hmac.Equal([]byte(sig), []byte(want)) {
+		t.Errorf("%s = %q, want %q", SignatureHeader, sig, want)
+	}
+	if string(got.body) != string(body) {
+		t.Errorf("body = %s, want %s", got.body, body)
+	}
+}
+
+func TestNoSignatureWithoutSecret(t *testing.T) {
+	r := newReceiver(t, always(http.StatusOK))
+	if err := newSender(Settings{Timeout: time.Second}).post(make(chan struct{}), r.URL, []byte(`{}`)); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if got := <-r.got; got.header.Get(SignatureHeader) != "" || got.header.Get(TimestampHeader) != "" {
+		t.Errorf("unsigned request has signature headers: %v", got.header)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	const backoff = 20 * time.Millisecond
+	tests := []struct {
+		name     string
+		status   func(int) int
+		attempts int
+		ok       bool
+	}{
+		{"server error", always(http.StatusInternalServerError), 3, false},
+		{"too many requests", always(http.StatusTooManyRequests), 3, false},
+		{"client error", always(http.StatusBadRequest), 1, false},
+		{"not found", always(http.StatusNotFound), 1, false},
+		{"recovers", func(attempt int) int {
+			if attempt < 2 {
+				return http.StatusServiceUnavailable
+			}
+			return http.StatusNoContent
+		}, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReceiver(t, tt.status)
+			s := newSender(Settings{Timeout: time.Second, MaxRetries: 2, Backoff: backoff})
+
+			err := s.post(make(chan struct{}), r.URL, []byte(`{}`))
+			if (err == nil) != tt.ok {
+				t.Fatalf("post error = %v, want ok=%t", err, tt.ok)
+			}
+			reqs := r.all()
+			if len(reqs) != tt.attempts {
+				t.Fatalf("attempts = %d, want %d", len(reqs), tt.attempts)
+			}
+			// задержка перед каждым повтором удваивается: backoff, 2·backoff, ...
+			for i := 1; i < len(reqs); i++ {
+				want := backoff << (i - 1)
+				if gap := reqs[i].at.Sub(reqs[i-1].at); gap < want {
+					t.Errorf("delay before retry %d = %v, want at least %v", i, gap, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetriesStopOnShutdown(t *testing.T) {
+	r := newReceiver(t, always(http.StatusBadGateway))
+	s := newSender(Settings{Timeout: time.Second, MaxRetries: 5, Backoff: time.Hour})
+	stop := make(chan struct{})
+	close(stop)
+
+	if err := s.post(stop, r.URL, []byte(`{}`)); err == nil {
+		t.Fatal("post succeeded against a failing receiver")
+	}
+	if n := r.count(); n != 1 {
+		t.Errorf("attempts after shutdown = %d, want 1", n)
+	}
+}
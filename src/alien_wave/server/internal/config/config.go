@@ -0,0 +1,461 @@
+// github.com/lonmouth/alien_wave/server/internal/config/config.go
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/lonmouth/alien_wave/server/internal/generator"
+	"gopkg.in/yaml.v3"
+)
+
+// максимальное количество дополнительных каналов
+const MaxChannels = 32
+
+// поведение рассылки при переполнении буфера подписчика
+const (
+	SlowConsumerDrop       = "drop"       // сообщения, не поместившиеся в буфер, пропускаются; подписчик увидит пропуск номеров
+	SlowConsumerDisconnect = "disconnect" // подписчик отключается с RESOURCE_EXHAUSTED
+)
+
+// диапазон, из которого равномерно выбирается параметр распределения
+type Range struct {
+	Min float64 `yaml:"min"`
+	Max float64 `yaml:"max"`
+}
+
+// настройки keepalive gRPC-сервера
+type Keepalive struct {
+	Time                  time.Duration `yaml:"time"`                     // через сколько простоя сервер пингует клиента
+	Timeout               time.Duration `yaml:"timeout"`                  // сколько ждать ответа на ping до закрытия соединения
+	MinTime               time.Duration `yaml:"min_time"`                 // минимальный допустимый интервал ping со стороны клиента
+	PermitWithoutStream   bool          `yaml:"permit_without_stream"`    // разрешать ли клиенту ping без активных потоков
+	MaxConnectionIdle     time.Duration `yaml:"max_connection_idle"`      // закрывать соединение без потоков после такого простоя (0 - не закрывать)
+	MaxConnectionAge      time.Duration `yaml:"max_connection_age"`       // максимальный возраст соединения (0 - без ограничений)
+	MaxConnectionAgeGrace time.Duration `yaml:"max_connection_age_grace"` // сколько потоки старого соединения могут завершаться (0 - без ограничений)
+}
+
+type Config struct {
+	ListenAddr           string        `yaml:"listen_addr"`            // адрес, который слушает сервер
+	SendInterval         time.Duration `yaml:"send_interval"`          // интервал между сообщениями
+	ConnectionTimeout    time.Duration `yaml:"connection_timeout"`     // таймаут установки соединения
+	Mean                 Range         `yaml:"mean"`                   // диапазон математического ожидания (μ)
+	STD                  Range         `yaml:"std"`                    // диапазон стандартного отклонения (σ)
+	MaxConcurrentStreams uint32        `yaml:"max_concurrent_streams"` // максимум одновременных потоков на соединение (0 - без ограничений)
+	Keepalive            Keepalive     `yaml:"keepalive"`              // настройки keepalive
+	Generator            string        `yaml:"generator"`              // модель генератора значений
+	Channels             uint          `yaml:"channels"`               // количество дополнительных каналов (0 - только frequency)
+	ChannelCorrelation   float64       `yaml:"channel_correlation"`    // попарная корреляция каналов, включая frequency
+	HeartbeatInterval    time.Duration `yaml:"heartbeat_interval"`     // максимальная пауза между сообщениями потока Stream (0 - без heartbeat)
+	SessionPoints        uint64        `yaml:"session_points"`         // количество точек сессии, после которого начинается новая (0 - без ограничений)
+	DiscloseParameters   bool          `yaml:"disclose_parameters"`    // отправлять ли истинные параметры генератора в начале сессии
+	MaxUnacked           uint          `yaml:"max_unacked"`            // сколько неподтвержденных точек Connect хранится; при заполнении отправка ждет подтверждений
+	ResumeTimeout        time.Duration `yaml:"resume_timeout"`         // сколько сессия Connect ждет переподключения клиента
+	SubscriberBuffer     uint          `yaml:"subscriber_buffer"`      // сколько сообщений рассылки ждут отправки каждому подписчику
+	SlowConsumer         string        `yaml:"slow_consumer"`          // что делать с подписчиком, чей буфер переполнен: drop или disconnect
+	StreamLimit          uint          `yaml:"stream_limit"`           // максимум одновременных потоков сервера (0 - без ограничений)
+	PeerStreamLimit      uint          `yaml:"peer_stream_limit"`      // максимум одновременных потоков с одного адреса (0 - без ограничений)
+	MaxStreamLifetime    time.Duration `yaml:"max_stream_lifetime"`    // максимальная длительность потока (0 - без ограничений)
+	MaxSendRate          float64       `yaml:"max_send_rate"`          // максимум точек в секунду на поток, в том числе по запросу Flow (0 - без ограничений)
+	MaxRecvRate          float64       `yaml:"max_recv_rate"`          // максимум сообщений клиента в секунду на поток Connect (0 - без ограничений)
+	MetricsAddr          string        `yaml:"metrics_addr"`           // адрес HTTP /metrics (пусто - метрики отключены)
+	Reflection           bool          `yaml:"reflection"`             // регистрировать ли gRPC reflection для grpcurl и подобных инструментов
+}
+
+// значения по умолчанию совпадают с прежними захардкоженными константами
+func defaults() *Config {
+	return &Config{
+		ListenAddr:        ":50051",
+		SendInterval:      1 * time.Second,
+		ConnectionTimeout: 5 * time.Second,
+		Mean:              Range{Min: -10, Max: 10},
+		STD:               Range{Min: 0.3, Max: 1.5},
+		Keepalive: Keepalive{
+			Time:    2 * time.Hour,
+			Timeout: 20 * time.Second,
+			MinTime: 5 * time.Minute,
+		},
+		Generator:          generator.Normal,
+		ChannelCorrelation: 0.8,
+		HeartbeatInterval:  5 * time.Second,
+		MaxUnacked:         1000,
+		ResumeTimeout:      time.Minute,
+		SubscriberBuffer:   256,
+		SlowConsumer:       SlowConsumerDrop,
+	}
+}
+
+// функция загружает конфигурацию. Приоритет источников (от низшего к высшему):
+// значения по умолчанию, YAML-файл (-config или CONFIG_FILE), переменные окружения, флаги.
+// Для -h и -help справка выводится в stderr, а возвращается flag.ErrHelp
+func Load(args []string) (*Config, error) {
+	return load(args, true)
+}
+
+// FromFlags собирает конфигурацию только из значений по умолчанию, флагов args и файла явного флага -config:
+// переменные окружения и CONFIG_FILE не читаются. Тесты не зависят от окружения разработчика
+func FromFlags(args []string) (*Config, error) {
+	return load(args, false)
+}
+
+// env - учитывать ли переменные окружения и CONFIG_FILE
+func load(args []string, env bool) (*Config, error) {
+	cfg := defaults()
+
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fl := defaults() // значения флагов применяются только если флаг задан явно
+	defaultFile := ""
+	if env {
+		defaultFile = getEnv("CONFIG_FILE", "")
+	}
+	configFile := fs.String("config", defaultFile, "путь к YAML-файлу конфигурации")
+	fs.StringVar(&fl.ListenAddr, "listen", fl.ListenAddr, "адрес, который слушает сервер")
+	fs.DurationVar(&fl.SendInterval, "send-interval", fl.SendInterval, "интервал между сообщениями")
+	fs.DurationVar(&fl.ConnectionTimeout, "connection-timeout", fl.ConnectionTimeout, "таймаут установки соединения")
+	fs.Float64Var(&fl.Mean.Min, "mean-min", fl.Mean.Min, "нижняя граница μ")
+	fs.Float64Var(&fl.Mean.Max, "mean-max", fl.Mean.Max, "верхняя граница μ")
+	fs.Float64Var(&fl.STD.Min, "std-min", fl.STD.Min, "нижняя граница σ")
+	fs.Float64Var(&fl.STD.Max, "std-max", fl.STD.Max, "верхняя граница σ")
+	maxStreams := fs.Uint("max-streams", uint(fl.MaxConcurrentStreams), "максимум одновременных потоков на соединение")
+	fs.DurationVar(&fl.Keepalive.Time, "keepalive-time", fl.Keepalive.Time, "интервал keepalive ping")
+	fs.DurationVar(&fl.Keepalive.Timeout, "keepalive-timeout", fl.Keepalive.Timeout, "таймаут ответа на keepalive ping")
+	fs.DurationVar(&fl.Keepalive.MinTime, "keepalive-min-time", fl.Keepalive.MinTime, "минимальный интервал ping клиента")
+	fs.BoolVar(&fl.Keepalive.PermitWithoutStream, "keepalive-permit-without-stream", fl.Keepalive.PermitWithoutStream, "разрешить ping без активных потоков")
+	fs.DurationVar(&fl.Keepalive.MaxConnectionIdle, "keepalive-max-connection-idle", fl.Keepalive.MaxConnectionIdle, "закрывать соединение без потоков после простоя (0 - не закрывать)")
+	fs.DurationVar(&fl.Keepalive.MaxConnectionAge, "keepalive-max-connection-age", fl.Keepalive.MaxConnectionAge, "максимальный возраст соединения (0 - без ограничений)")
+	fs.DurationVar(&fl.Keepalive.MaxConnectionAgeGrace, "keepalive-max-connection-age-grace", fl.Keepalive.MaxConnectionAgeGrace, "время завершения потоков старого соединения")
+	fs.StringVar(&fl.Generator, "generator", fl.Generator, fmt.Sprintf("модель генератора %v", generator.Models()))
+	fs.UintVar(&fl.Channels, "channels", fl.Channels, "количество дополнительных каналов")
+	fs.Float64Var(&fl.ChannelCorrelation, "channel-correlation", fl.ChannelCorrelation, "попарная корреляция каналов, [0, 1)")
+	fs.DurationVar(&fl.HeartbeatInterval, "heartbeat-interval", fl.HeartbeatInterval, "максимальная пауза между сообщениями потока (0 - без heartbeat)")
+	fs.Uint64Var(&fl.SessionPoints, "session-points", fl.SessionPoints, "количество точек сессии (0 - без ограничений)")
+	fs.BoolVar(&fl.DiscloseParameters, "disclose-parameters", fl.DiscloseParameters, "отправлять истинные параметры генератора в начале сессии")
+	fs.UintVar(&fl.MaxUnacked, "max-unacked", fl.MaxUnacked, "сколько неподтвержденных точек Connect хранится")
+	fs.DurationVar(&fl.ResumeTimeout, "resume-timeout", fl.ResumeTimeout, "сколько сессия Connect ждет переподключения клиента")
+	fs.UintVar(&fl.SubscriberBuffer, "subscriber-buffer", fl.SubscriberBuffer, "буфер сообщений каждого подписчика рассылки")
+	fs.StringVar(&fl.SlowConsumer, "slow-consumer", fl.SlowConsumer, "подписчик с переполненным буфером: drop или disconnect")
+	fs.UintVar(&fl.StreamLimit, "stream-limit", fl.StreamLimit, "максимум одновременных потоков сервера (0 - без ограничений)")
+	fs.UintVar(&fl.PeerStreamLimit, "peer-stream-limit", fl.PeerStreamLimit, "максимум одновременных потоков с одного адреса (0 - без ограничений)")
+	fs.DurationVar(&fl.MaxStreamLifetime, "max-stream-lifetime", fl.MaxStreamLifetime, "максимальная длительность потока (0 - без ограничений)")
+	fs.Float64Var(&fl.MaxSendRate, "max-send-rate", fl.MaxSendRate, "максимум точек в секунду на поток (0 - без ограничений)")
+	fs.Float64Var(&fl.MaxRecvRate, "max-recv-rate", fl.MaxRecvRate, "максимум сообщений клиента в секунду на поток Connect (0 - без ограничений)")
+	fs.StringVar(&fl.MetricsAddr, "metrics-addr", fl.MetricsAddr, "адрес HTTP /metrics (пусто - метрики отключены)")
+	fs.BoolVar(&fl.Reflection, "reflection", fl.Reflection, "регистрировать gRPC reflection")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile != "" {
+		if err := loadFile(*configFile, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	if env {
+		applyEnv(cfg, &errs)
+	}
+
+	// переносим в конфигурацию только явно заданные флаги
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "listen":
+			cfg.ListenAddr = fl.ListenAddr
+		case "send-interval":
+			cfg.SendInterval = fl.SendInterval
+		case "connection-timeout":
+			cfg.ConnectionTimeout = fl.ConnectionTimeout
+		case "mean-min":
+			cfg.Mean.Min = fl.Mean.Min
+		case "mean-max":
+			cfg.Mean.Max = fl.Mean.Max
+		case "std-min":
+			cfg.STD.Min = fl.STD.Min
+		case "std-max":
+			cfg.STD.Max = fl.STD.Max
+		case "max-streams":
+			cfg.MaxConcurrentStreams = uint32(*maxStreams)
+		case "keepalive-time":
+			cfg.Keepalive.Time = fl.Keepalive.Time
+		case "keepalive-timeout":
+			cfg.Keepalive.Timeout = fl.Keepalive.Timeout
+		case "keepalive-min-time":
+			cfg.Keepalive.MinTime = fl.Keepalive.MinTime
+		case "keepalive-permit-without-stream":
+			cfg.Keepalive.PermitWithoutStream = fl.Keepalive.PermitWithoutStream
+		case "keepalive-max-connection-idle":
+			cfg.Keepalive.MaxConnectionIdle = fl.Keepalive.MaxConnectionIdle
+		case "keepalive-max-connection-age":
+			cfg.Keepalive.MaxConnectionAge = fl.Keepalive.MaxConnectionAge
+		case "keepalive-max-connection-age-grace":
+			cfg.Keepalive.MaxConnectionAgeGrace = fl.Keepalive.MaxConnectionAgeGrace
+		case "generator":
+			cfg.Generator = fl.Generator
+		case "channels":
+			cfg.Channels = fl.Channels
+		case "channel-correlation":
+			cfg.ChannelCorrelation = fl.ChannelCorrelation
+		case "heartbeat-interval":
+			cfg.HeartbeatInterval = fl.HeartbeatInterval
+		case "session-points":
+			cfg.SessionPoints = fl.SessionPoints
+		case "disclose-parameters":
+			cfg.DiscloseParameters = fl.DiscloseParameters
+		case "max-unacked":
+			cfg.MaxUnacked = fl.MaxUnacked
+		case "resume-timeout":
+			cfg.ResumeTimeout = fl.ResumeTimeout
+		case "subscriber-buffer":
+			cfg.SubscriberBuffer = fl.SubscriberBuffer
+		case "slow-consumer":
+			cfg.SlowConsumer = fl.SlowConsumer
+		case "stream-limit":
+			cfg.StreamLimit = fl.StreamLimit
+		case "peer-stream-limit":
+			cfg.PeerStreamLimit = fl.PeerStreamLimit
+		case "max-stream-lifetime":
+			cfg.MaxStreamLifetime = fl.MaxStreamLifetime
+		case "max-send-rate":
+			cfg.MaxSendRate = fl.MaxSendRate
+		case "max-recv-rate":
+			cfg.MaxRecvRate = fl.MaxRecvRate
+		case "metrics-addr":
+			cfg.MetricsAddr = fl.MetricsAddr
+		case "reflection":
+			cfg.Reflection = fl.Reflection
+		}
+	})
+
+	errs = append(errs, cfg.Validate())
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// читает YAML-файл поверх текущих значений
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// переопределяет значения переменными окружения, если они заданы
+func applyEnv(cfg *Config, errs *[]error) {
+	if v := getEnv("LISTEN_ADDR", ""); v != "" {
+		cfg.ListenAddr = v
+	}
+	envDuration("SEND_INTERVAL", &cfg.SendInterval, errs)
+	envDuration("CONNECTION_TIMEOUT", &cfg.ConnectionTimeout, errs)
+	envFloat("MEAN_MIN", &cfg.Mean.Min, errs)
+	envFloat("MEAN_MAX", &cfg.Mean.Max, errs)
+	envFloat("STD_MIN", &cfg.STD.Min, errs)
+	envFloat("STD_MAX", &cfg.STD.Max, errs)
+	if v := getEnv("MAX_CONCURRENT_STREAMS", ""); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("MAX_CONCURRENT_STREAMS: %w", err))
+		} else {
+			cfg.MaxConcurrentStreams = uint32(n)
+		}
+	}
+	envDuration("KEEPALIVE_TIME", &cfg.Keepalive.Time, errs)
+	envDuration("KEEPALIVE_TIMEOUT", &cfg.Keepalive.Timeout, errs)
+	envDuration("KEEPALIVE_MIN_TIME", &cfg.Keepalive.MinTime, errs)
+	if v := getEnv("KEEPALIVE_PERMIT_WITHOUT_STREAM", ""); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("KEEPALIVE_PERMIT_WITHOUT_STREAM: %w", err))
+		} else {
+			cfg.Keepalive.PermitWithoutStream = b
+		}
+	}
+	envDuration("KEEPALIVE_MAX_CONNECTION_IDLE", &cfg.Keepalive.MaxConnectionIdle, errs)
+	envDuration("KEEPALIVE_MAX_CONNECTION_AGE", &cfg.Keepalive.MaxConnectionAge, errs)
+	envDuration("KEEPALIVE_MAX_CONNECTION_AGE_GRACE", &cfg.Keepalive.MaxConnectionAgeGrace, errs)
+	if v := getEnv("GENERATOR", ""); v != "" {
+		cfg.Generator = v
+	}
+	if v := getEnv("CHANNELS", ""); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("CHANNELS: %w", err))
+		} else {
+			cfg.Channels = uint(n)
+		}
+	}
+	envFloat("CHANNEL_CORRELATION", &cfg.ChannelCorrelation, errs)
+	envDuration("HEARTBEAT_INTERVAL", &cfg.HeartbeatInterval, errs)
+	if v := getEnv("SESSION_POINTS", ""); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("SESSION_POINTS: %w", err))
+		} else {
+			cfg.SessionPoints = n
+		}
+	}
+	if v := getEnv("DISCLOSE_PARAMETERS", ""); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("DISCLOSE_PARAMETERS: %w", err))
+		} else {
+			cfg.DiscloseParameters = b
+		}
+	}
+	if v := getEnv("MAX_UNACKED", ""); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("MAX_UNACKED: %w", err))
+		} else {
+			cfg.MaxUnacked = uint(n)
+		}
+	}
+	envDuration("RESUME_TIMEOUT", &cfg.ResumeTimeout, errs)
+	if v := getEnv("SUBSCRIBER_BUFFER", ""); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("SUBSCRIBER_BUFFER: %w", err))
+		} else {
+			cfg.SubscriberBuffer = uint(n)
+		}
+	}
+	if v := getEnv("SLOW_CONSUMER", ""); v != "" {
+		cfg.SlowConsumer = v
+	}
+	envUint("STREAM_LIMIT", &cfg.StreamLimit, errs)
+	envUint("PEER_STREAM_LIMIT", &cfg.PeerStreamLimit, errs)
+	envDuration("MAX_STREAM_LIFETIME", &cfg.MaxStreamLifetime, errs)
+	envFloat("MAX_SEND_RATE", &cfg.MaxSendRate, errs)
+	envFloat("MAX_RECV_RATE", &cfg.MaxRecvRate, errs)
+	if v := getEnv("METRICS_ADDR", ""); v != "" {
+		cfg.MetricsAddr = v
+	}
+	if v := getEnv("REFLECTION", ""); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("REFLECTION: %w", err))
+		} else {
+			cfg.Reflection = b
+		}
+	}
+}
+
+// проверяет согласованность настроек
+func (c *Config) Validate() error {
+	var errs []error
+	if c.ListenAddr == "" {
+		errs = append(errs, errors.New("listen address must not be empty"))
+	}
+	if c.SendInterval <= 0 {
+		errs = append(errs, fmt.Errorf("send interval must be positive, got %v", c.SendInterval))
+	}
+	if c.Mean.Min > c.Mean.Max {
+		errs = append(errs, fmt.Errorf("mean range is inverted: [%g, %g]", c.Mean.Min, c.Mean.Max))
+	}
+	if c.STD.Min <= 0 || c.STD.Min > c.STD.Max {
+		errs = append(errs, fmt.Errorf("std range must be positive and ordered: [%g, %g]", c.STD.Min, c.STD.Max))
+	}
+	if !generator.Known(c.Generator) {
+		errs = append(errs, fmt.Errorf("unknown generator %q, expected one of %v", c.Generator, generator.Models()))
+	}
+	if c.Channels > MaxChannels {
+		errs = append(errs, fmt.Errorf("channels must be at most %d, got %d", MaxChannels, c.Channels))
+	}
+	if c.ChannelCorrelation < 0 || c.ChannelCorrelation >= 1 {
+		errs = append(errs, fmt.Errorf("channel correlation must be in [0, 1), got %g", c.ChannelCorrelation))
+	}
+	if c.HeartbeatInterval < 0 {
+		errs = append(errs, fmt.Errorf("heartbeat interval must not be negative, got %v", c.HeartbeatInterval))
+	}
+	if c.MaxUnacked == 0 {
+		errs = append(errs, errors.New("max unacked points must be positive"))
+	}
+	if c.ResumeTimeout < 0 {
+		errs = append(errs, fmt.Errorf("resume timeout must not be negative, got %v", c.ResumeTimeout))
+	}
+	if c.SubscriberBuffer == 0 {
+		errs = append(errs, errors.New("subscriber buffer must be positive"))
+	}
+	if c.SlowConsumer != SlowConsumerDrop && c.SlowConsumer != SlowConsumerDisconnect {
+		errs = append(errs, fmt.Errorf("unknown slow consumer policy %q, expected %s or %s", c.SlowConsumer, SlowConsumerDrop, SlowConsumerDisconnect))
+	}
+	if c.PeerStreamLimit > 0 && c.StreamLimit > 0 && c.PeerStreamLimit > c.StreamLimit {
+		errs = append(errs, fmt.Errorf("peer stream limit %d exceeds stream limit %d", c.PeerStreamLimit, c.StreamLimit))
+	}
+	if c.MaxStreamLifetime < 0 {
+		errs = append(errs, fmt.Errorf("max stream lifetime must not be negative, got %v", c.MaxStreamLifetime))
+	}
+	if c.MaxSendRate < 0 {
+		errs = append(errs, fmt.Errorf("max send rate must not be negative, got %g", c.MaxSendRate))
+	} else if c.MaxSendRate > 0 && c.SendInterval > 0 && c.SendInterval < c.MinSendInterval() {
+		errs = append(errs, fmt.Errorf("send interval %v exceeds max send rate %g/s", c.SendInterval, c.MaxSendRate))
+	}
+	if c.MaxRecvRate < 0 {
+		errs = append(errs, fmt.Errorf("max recv rate must not be negative, got %g", c.MaxRecvRate))
+	}
+	return errors.Join(errs...)
+}
+
+// наименьший интервал между точками, допустимый MaxSendRate (0 - без ограничений)
+func (c *Config) MinSendInterval() time.Duration {
+	if c.MaxSendRate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / c.MaxSendRate)
+}
+
+// получает значение переменной окружения по ключу. Если переменная не установлена, возвращает значение по умолчанию
+func getEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func envFloat(key string, dst *float64, errs *[]error) {
+	v := getEnv(key, "")
+	if v == "" {
+		return
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %w", key, err))
+		return
+	}
+	*dst = f
+}
+
+func envUint(key string, dst *uint, errs *[]error) {
+	v := getEnv(key, "")
+	if v == "" {
+		return
+	}
+	n, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %w", key, err))
+		return
+	}
+	*dst = uint(n)
+}
+
+func envDuration(key string, dst *time.Duration, errs *[]error) {
+	v := getEnv(key, "")
+	if v == "" {
+		return
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %w", key, err))
+		return
+	}
+	*dst = d
+}
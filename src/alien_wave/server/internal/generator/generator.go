@@ -0,0 +1,112 @@
+// github.com/lonmouth/alien_wave/server/internal/generator/generator.go
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// поддерживаемые модели генератора
+const (
+	Normal  = "normal"  // нормальное распределение
+	Uniform = "uniform" // равномерное распределение с теми же μ и σ
+	Laplace = "laplace" // распределение Лапласа (тяжелые хвосты) с теми же μ и σ
+)
+
+// Generator выдает очередное значение частоты
+type Generator interface {
+	Next() float64
+}
+
+// Models возвращает список поддерживаемых моделей
+func Models() []string {
+	return []string{Normal, Uniform, Laplace}
+}
+
+// Known сообщает, поддерживается ли модель
+func Known(model string) bool {
+	for _, m := range Models() {
+		if m == model {
+			return true
+		}
+	}
+	return false
+}
+
+// New создает генератор выбранной модели с математическим ожиданием mean и стандартным отклонением std
+func New(model string, r *rand.Rand, mean, std float64) (Generator, error) {
+	switch model {
+	case Normal:
+		return &normal{r: r, mean: mean, std: std}, nil
+	case Uniform:
+		half := std * math.Sqrt(3) // у U[a, b] σ = (b-a)/√12
+		return &uniform{r: r, low: mean - half, width: 2 * half}, nil
+	case Laplace:
+		return &laplace{r: r, mean: mean, scale: std / math.Sqrt2}, nil // у Laplace(μ, b) σ = b·√2
+	default:
+		return nil, fmt.Errorf("unknown generator model %q", model)
+	}
+}
+
+type normal struct {
+	r    *rand.Rand
+	mean float64
+	std  float64
+}
+
+func (g *normal) Next() float64 { return g.r.NormFloat64()*g.std + g.mean }
+
+type uniform struct {
+	r     *rand.Rand
+	low   float64
+	width float64
+}
+
+func (g *uniform) Next() float64 { return g.low + g.r.Float64()*g.width }
+
+type laplace struct {
+	r     *rand.Rand
+	mean  float64
+	scale float64
+}
+
+func (g *laplace) Next() float64 {
+	u := g.r.Float64() - 0.5 // обратное преобразование функции распределения
+	return g.mean - g.scale*math.Copysign(math.Log(1-2*math.Abs(u)), u)
+}
+
+// Correlated выдает значения нескольких каналов с попарной корреляцией rho:
+// x_i = μ_i + σ_i·(√rho·f + √(1-rho)·e_i), где общий фактор f и шумы e_i взяты из одной модели с μ=0, σ=1
+type Correlated struct {
+	noise Generator
+	means []float64
+	stds  []float64
+	rho   float64
+}
+
+// NewCorrelated создает генератор каналов выбранной модели; means и stds задают μ и σ каждого канала
+func NewCorrelated(model string, r *rand.Rand, means, stds []float64, rho float64) (*Correlated, error) {
+	if len(means) != len(stds) {
+		return nil, fmt.Errorf("channel means and stds differ in length: %d != %d", len(means), len(stds))
+	}
+	if rho < 0 || rho >= 1 {
+		return nil, fmt.Errorf("channel correlation must be in [0, 1), got %g", rho)
+	}
+	noise, err := New(model, r, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &Correlated{noise: noise, means: means, stds: stds, rho: rho}, nil
+}
+
+// Next возвращает значения всех каналов для очередной точки
+func (c *Correlated) Next() []float64 {
+	common := c.noise.Next()
+	values := make([]float64, len(c.means))
+	for i := range values {
+		e := math.Sqrt(c.rho)*common + math.Sqrt(1-c.rho)*c.noise.Next()
+		values[i] = c.means[i] + c.stds[i]*e
+	}
+	return values
+}
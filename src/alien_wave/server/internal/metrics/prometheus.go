@@ -0,0 +1,154 @@
+// github.com/lonmouth/alien_wave/server/internal/metrics/prometheus.go
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"github.com/lonmouth/alien_wave/server/internal/config"
+	"github.com/lonmouth/alien_wave/server/internal/service"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus экспортирует показатели сервера. Метки адресов клиентов не используются,
+// чтобы количество рядов не зависело от количества клиентов; ряды параметров сессии удаляются с ее завершением
+type Prometheus struct {
+	registry    *prometheus.Registry
+	streams     prometheus.Gauge
+	peers       prometheus.Gauge
+	busiestPeer prometheus.Gauge
+	exceeded    *prometheus.CounterVec
+	sessions    prometheus.Gauge
+	started     prometheus.Counter
+	ended       *prometheus.CounterVec
+	sent        *prometheus.CounterVec
+	sendErrors  *prometheus.CounterVec
+	sendLatency prometheus.Histogram
+	mean        *prometheus.GaugeVec
+	std         *prometheus.GaugeVec
+}
+
+func New(cfg *config.Config) *Prometheus {
+	p := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		streams: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "alien_wave_server_streams",
+			Help: "Открытые потоки (StreamData, Stream, Connect, Subscribe).",
+		}),
+		peers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "alien_wave_server_stream_peers",
+			Help: "Адреса клиентов с открытыми потоками.",
+		}),
+		busiestPeer: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "alien_wave_server_busiest_peer_streams",
+			Help: "Наибольшее количество открытых потоков одного адреса.",
+		}),
+		exceeded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alien_wave_server_limit_exceeded_total",
+			Help: "Потоки, отклоненные или завершенные с RESOURCE_EXHAUSTED, по ограничениям: streams, peer_streams, lifetime, recv_rate.",
+		}, []string{"limit"}),
+		sessions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "alien_wave_server_sessions",
+			Help: "Активные сессии генератора.",
+		}),
+		started: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alien_wave_server_sessions_started_total",
+			Help: "Начатые сессии генератора.",
+		}),
+		ended: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alien_wave_server_sessions_ended_total",
+			Help: "Завершенные сессии по причинам: completed, ended, server_shutdown, expired.",
+		}, []string{"reason"}),
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alien_wave_server_messages_sent_total",
+			Help: "Сообщения, отправленные клиентам, по видам: start, data, heartbeat, end.",
+		}, []string{"kind"}),
+		sendErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alien_wave_server_send_errors_total",
+			Help: "Ошибки отправки сообщений клиентам по видам сообщений.",
+		}, []string{"kind"}),
+		sendLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "alien_wave_server_send_duration_seconds",
+			Help:    "Время отправки сообщения клиенту, включая ожидание окна управления потоком.",
+			Buckets: prometheus.ExponentialBuckets(0.0001, 4, 8), // 100µs - 1.6s
+		}),
+		mean: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "alien_wave_server_session_mean",
+			Help: "Истинное μ генератора активной сессии по каналам (frequency - основной канал).",
+		}, []string{"session", "channel"}),
+		std: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "alien_wave_server_session_std",
+			Help: "Истинное σ генератора активной сессии по каналам (frequency - основной канал).",
+		}, []string{"session", "channel"}),
+	}
+	limits := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "alien_wave_server_limit",
+		Help: "Настроенные ограничения (0 - без ограничений): streams, peer_streams, lifetime_seconds, send_rate, recv_rate.",
+	}, []string{"limit"})
+	limits.WithLabelValues(service.LimitStreams).Set(float64(cfg.StreamLimit))
+	limits.WithLabelValues(service.LimitPeerStreams).Set(float64(cfg.PeerStreamLimit))
+	limits.WithLabelValues("lifetime_seconds").Set(cfg.MaxStreamLifetime.Seconds())
+	limits.WithLabelValues("send_rate").Set(cfg.MaxSendRate)
+	limits.WithLabelValues(service.LimitRecvRate).Set(cfg.MaxRecvRate)
+
+	p.registry.MustRegister(p.streams, p.peers, p.busiestPeer, p.exceeded, limits,
+		p.sessions, p.started, p.ended, p.sent, p.sendErrors, p.sendLatency, p.mean, p.std,
+		prometheus.NewGoCollector(), prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
+	for _, limit := range []string{service.LimitStreams, service.LimitPeerStreams, service.LimitLifetime, service.LimitRecvRate} {
+		p.exceeded.WithLabelValues(limit) // ряды видны со значением 0 до первого нарушения
+	}
+	for _, reason := range []transmitter.SessionEnd_Reason{transmitter.SessionEnd_REASON_COMPLETED,
+		transmitter.SessionEnd_REASON_ENDED, transmitter.SessionEnd_REASON_SERVER_SHUTDOWN} {
+		p.ended.WithLabelValues(service.ReasonLabel(reason))
+	}
+	p.ended.WithLabelValues(service.ReasonExpired)
+	for _, kind := range []string{"start", "data", "heartbeat", "end"} {
+		p.sent.WithLabelValues(kind)
+		p.sendErrors.WithLabelValues(kind)
+	}
+	return p
+}
+
+func (p *Prometheus) StreamsChanged(u service.Usage) {
+	p.streams.Set(float64(u.Streams))
+	p.peers.Set(float64(u.Peers))
+	p.busiestPeer.Set(float64(u.BusiestPeer))
+}
+
+func (p *Prometheus) LimitExceeded(limit string) {
+	p.exceeded.WithLabelValues(limit).Inc()
+}
+
+func (p *Prometheus) SessionStarted(id string, params *transmitter.GeneratorParameters) {
+	p.sessions.Inc()
+	p.started.Inc()
+	p.mean.WithLabelValues(id, "frequency").Set(params.Mean)
+	p.std.WithLabelValues(id, "frequency").Set(params.Std)
+	for _, ch := range params.Channels {
+		p.mean.WithLabelValues(id, ch.Name).Set(ch.Mean)
+		p.std.WithLabelValues(id, ch.Name).Set(ch.Std)
+	}
+}
+
+func (p *Prometheus) SessionEnded(id, reason string) {
+	p.sessions.Dec()
+	p.ended.WithLabelValues(reason).Inc()
+	p.mean.DeletePartialMatch(prometheus.Labels{"session": id})
+	p.std.DeletePartialMatch(prometheus.Labels{"session": id})
+}
+
+func (p *Prometheus) MessageSent(kind string, latency time.Duration, err error) {
+	if err != nil {
+		p.sendErrors.WithLabelValues(kind).Inc()
+		return
+	}
+	p.sent.WithLabelValues(kind).Inc()
+	p.sendLatency.Observe(latency.Seconds())
+}
+
+// обработчик /metrics
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,67 @@
+// github.com/lonmouth/alien_wave/server/internal/service/control.go
+package service
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"time"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListSessions возвращает все сессии сервера в порядке создания
+func (s *Server) ListSessions(ctx context.Context, req *transmitter.ListSessionsRequest) (*transmitter.ListSessionsResponse, error) {
+	resp := &transmitter.ListSessionsResponse{}
+	for _, sess := range s.sessions.list() {
+		resp.Sessions = append(resp.Sessions, sess.info())
+	}
+	return resp, nil
+}
+
+// GetSession возвращает состояние сессии вместе с истинными μ и σ генератора для проверки детектора
+func (s *Server) GetSession(ctx context.Context, req *transmitter.GetSessionRequest) (*transmitter.SessionInfo, error) {
+	sess, ok := s.sessions.get(req.SessionId)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "session %s not found or expired", req.SessionId)
+	}
+	return sess.info(), nil
+}
+
+// EndSession завершает сессию: ее поток отправляет SessionEnd с причиной REASON_ENDED и начинает новую сессию
+func (s *Server) EndSession(ctx context.Context, req *transmitter.EndSessionRequest) (*transmitter.Empty, error) {
+	sess, ok := s.sessions.get(req.SessionId)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "session %s not found or expired", req.SessionId)
+	}
+	if sess.stop(req.Detail) { // у сессии нет потока, который удалил бы ее сам
+		s.sessions.remove(sess.id)
+		s.ended(sess, ReasonLabel(transmitter.SessionEnd_REASON_ENDED))
+		log.Printf("Session %s ended by operator", sess.id)
+	}
+	return &transmitter.Empty{}, nil
+}
+
+// CreateSession создает сессию с заданными параметрами генератора. Точки сессии получает
+// Stream или Connect с ее ID; если за ResumeTimeout никто не подключится, сессия удаляется
+func (s *Server) CreateSession(ctx context.Context, req *transmitter.CreateSessionRequest) (*transmitter.SessionInfo, error) {
+	params := req.GetParameters()
+	if params == nil {
+		return nil, status.Error(codes.InvalidArgument, "parameters are required")
+	}
+	if err := checkParameters(params, s.cfg); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	sess, err := s.newSession(params, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	sess.release(nil, s.cfg.ResumeTimeout, func() {
+		s.sessions.remove(sess.id)
+		s.ended(sess, ReasonExpired)
+		log.Printf("Session %s expired without a stream", sess.id)
+	})
+	return sess.info(), nil
+}
@@ -0,0 +1,177 @@
+// github.com/lonmouth/alien_wave/server/internal/service/hub.go
+package service
+
+import (
+	"context"
+	"log"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"github.com/lonmouth/alien_wave/server/internal/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// подписчик рассылки сессии
+type subscriber struct {
+	ch         chan *transmitter.StreamMessage // сообщения, ожидающие отправки
+	done       chan struct{}                   // закрывается, когда рассылка для подписчика закончена
+	last       *transmitter.StreamMessage      // SessionEnd, отправляется после сообщений из буфера
+	err        error                           // причина отключения подписчика
+	disconnect bool                            // отключать при переполнении буфера вместо пропуска сообщений
+	dropped    uint64                          // сообщения, пропущенные из-за переполнения буфера
+}
+
+// рассылка, которую ведет сам сервер, пока у сессии есть подписчики, но нет клиентского потока
+type relay struct {
+	ctx   context.Context // отменяется, когда уходит последний подписчик
+	owner chan struct{}
+}
+
+// добавляет подписчика с буфером на buffer сообщений. Если сессию не отправляет ни один поток, возвращает relay:
+// вызывающий запускает рассылку сервера. Возвращает nil, если сессия уже удалена
+func (s *session) subscribe(buffer int, disconnect bool) (*subscriber, *relay) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, nil
+	}
+	sub := &subscriber{
+		ch:         make(chan *transmitter.StreamMessage, buffer),
+		done:       make(chan struct{}),
+		disconnect: disconnect,
+	}
+	if s.subs == nil {
+		s.subs = make(map[*subscriber]struct{})
+	}
+	s.subs[sub] = struct{}{}
+	if s.owner == nil {
+		return sub, s.relay()
+	}
+	return sub, nil
+}
+
+// удаляет подписчика и возвращает количество пропущенных им сообщений.
+// Без подписчиков рассылка сервера останавливается
+func (s *session) unsubscribe(sub *subscriber) (dropped uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.subs, sub)
+	if len(s.subs) == 0 && s.stopRelay != nil {
+		s.stopRelay()
+		s.stopRelay = nil
+	}
+	return sub.dropped
+}
+
+// передает сообщение потока подписчикам, не дожидаясь медленных. SessionEnd завершает рассылку
+func (s *session) publish(m *transmitter.StreamMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	end := m.GetEnd() != nil
+	for sub := range s.subs {
+		if end {
+			s.closeSubscriber(sub, m, nil)
+			continue
+		}
+		select {
+		case sub.ch <- m:
+		default:
+			if sub.disconnect {
+				s.closeSubscriber(sub, nil, status.Errorf(codes.ResourceExhausted, "subscriber buffer of %d messages is full", cap(sub.ch)))
+				continue
+			}
+			sub.dropped++
+		}
+	}
+}
+
+// завершает рассылку подписчику; вызывается под s.mu
+func (s *session) closeSubscriber(sub *subscriber, last *transmitter.StreamMessage, err error) {
+	delete(s.subs, sub)
+	sub.last, sub.err = last, err
+	close(sub.done)
+}
+
+// закрепляет сессию за рассылкой сервера; вызывается под s.mu
+func (s *session) relay() *relay {
+	if s.expiry != nil {
+		s.expiry.Stop()
+		s.expiry = nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.owner = make(chan struct{})
+	s.stopRelay = cancel
+	return &relay{ctx: ctx, owner: s.owner}
+}
+
+// Subscribe отправляет сообщения сессии, которую уже отправляет другой поток или сам сервер.
+// Все подписчики получают одни и те же точки, heartbeat и SessionEnd; переполнение буфера подписчика
+// обрабатывается по SlowConsumer и не задерживает остальных
+func (s *Server) Subscribe(req *transmitter.SubscribeRequest, stream transmitter.TransmitterService_SubscribeServer) error {
+	sess, ok := s.sessions.get(req.SessionId)
+	if !ok {
+		return status.Errorf(codes.NotFound, "session %s not found or expired", req.SessionId)
+	}
+	sub, r := sess.subscribe(int(s.cfg.SubscriberBuffer), s.cfg.SlowConsumer == config.SlowConsumerDisconnect)
+	if sub == nil {
+		return status.Errorf(codes.NotFound, "session %s not found or expired", req.SessionId)
+	}
+	defer func() {
+		if dropped := sess.unsubscribe(sub); dropped > 0 {
+			log.Printf("Subscriber of session %s dropped %d messages", sess.id, dropped)
+		}
+	}()
+	if r != nil {
+		go s.relay(sess, r)
+	}
+	log.Printf("New subscriber of session %s", sess.id)
+
+	if err := stream.Send(sess.start(sess.sendInterval(), s.cfg.DiscloseParameters, false)); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case m := <-sub.ch:
+			if err := stream.Send(m); err != nil {
+				return err
+			}
+		case <-sub.done:
+			for len(sub.ch) > 0 {
+				if err := stream.Send(<-sub.ch); err != nil {
+					return err
+				}
+			}
+			if sub.last != nil {
+				if err := stream.Send(sub.last); err != nil {
+					return err
+				}
+			}
+			return sub.err
+		}
+	}
+}
+
+// рассылает сессию подписчикам, пока ее не заберет клиентский поток или не уйдет последний подписчик
+func (s *Server) relay(sess *session, r *relay) {
+	log.Printf("Session %s is relayed by the server", sess.id)
+	c := &conn{
+		ctx:    r.ctx,
+		send:   func(*transmitter.StreamMessage) error { return nil }, // сообщения получают только подписчики
+		flow:   newFlow(s.cfg.SendInterval),
+		resume: s.cfg.ResumeTimeout,
+	}
+	sess.active.Lock()
+	done, _ := s.run(c, sess, r.owner)
+	sess.active.Unlock()
+	if !done {
+		sess.close()
+		s.sessions.remove(sess.id)
+		return
+	}
+	s.detach(c, sess, r.owner)
+}
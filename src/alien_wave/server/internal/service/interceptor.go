@@ -0,0 +1,97 @@
+// github.com/lonmouth/alien_wave/server/internal/service/interceptor.go
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StreamInterceptor применяет к потоковым RPC ограничения количества потоков, длительности потока
+// и частоты сообщений клиента и измеряет отправку сообщений. Нарушение ограничения завершает поток
+// со статусом RESOURCE_EXHAUSTED
+func (s *Server) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !strings.HasPrefix(info.FullMethod, "/"+transmitter.TransmitterService_ServiceDesc.ServiceName+"/") {
+			return handler(srv, ss) // служебные сервисы, например reflection, не ограничиваются
+		}
+		addr := peerHost(ss.Context())
+		if err := s.limits.acquire(addr); err != nil {
+			log.Printf("Stream %s from %s rejected: %v", info.FullMethod, addr, err)
+			return err
+		}
+		defer s.limits.release(addr)
+
+		ctx, cancel := context.WithCancel(ss.Context())
+		defer cancel()
+		if s.cfg.MaxStreamLifetime > 0 {
+			ctx, cancel = context.WithTimeout(ctx, s.cfg.MaxStreamLifetime)
+			defer cancel()
+		}
+		ls := &observedStream{ServerStream: ss, ctx: ctx, cancel: cancel, obs: s.obs}
+		if s.cfg.MaxRecvRate > 0 {
+			ls.recv = newBucket(s.cfg.MaxRecvRate)
+		}
+
+		err := handler(srv, ls)
+		switch {
+		case ls.violated.Load():
+			s.obs.LimitExceeded(LimitRecvRate)
+			err = ls.violation()
+		case errors.Is(ctx.Err(), context.DeadlineExceeded) && ss.Context().Err() == nil:
+			s.obs.LimitExceeded(LimitLifetime)
+			err = status.Errorf(codes.ResourceExhausted, "stream lifetime of %v exceeded", s.cfg.MaxStreamLifetime)
+		default:
+			return err
+		}
+		log.Printf("Stream %s from %s closed: %v", info.FullMethod, addr, err)
+		return err
+	}
+}
+
+// поток с ограниченной длительностью и частотой входящих сообщений, отправка которого измеряется
+type observedStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	cancel   context.CancelFunc
+	obs      Observer
+	recv     *bucket     // nil - частота не ограничена
+	violated atomic.Bool // клиент превысил частоту сообщений
+}
+
+func (ls *observedStream) Context() context.Context {
+	return ls.ctx
+}
+
+// время отправки включает ожидание окна управления потоком HTTP/2, то есть медленного клиента
+func (ls *observedStream) SendMsg(m any) error {
+	start := time.Now()
+	err := ls.ServerStream.SendMsg(m)
+	ls.obs.MessageSent(messageKind(m), time.Since(start), err)
+	return err
+}
+
+// сообщения клиента читает одна горутина, поэтому bucket не защищен
+func (ls *observedStream) RecvMsg(m any) error {
+	if err := ls.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if ls.recv != nil && !ls.recv.allow(time.Now()) {
+		ls.violated.Store(true)
+		ls.cancel()
+		return ls.violation()
+	}
+	return nil
+}
+
+func (ls *observedStream) violation() error {
+	return status.Errorf(codes.ResourceExhausted, "client message rate exceeds %g/s", ls.recv.rate)
+}
@@ -0,0 +1,124 @@
+// github.com/lonmouth/alien_wave/server/internal/service/limits.go
+package service
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/lonmouth/alien_wave/server/internal/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// ограничения, нарушение которых отклоняет или завершает поток
+const (
+	LimitStreams     = "streams"      // StreamLimit
+	LimitPeerStreams = "peer_streams" // PeerStreamLimit
+	LimitLifetime    = "lifetime"     // MaxStreamLifetime
+	LimitRecvRate    = "recv_rate"    // MaxRecvRate
+)
+
+// текущее использование потоков
+type Usage struct {
+	Streams     int // открытые потоки
+	Peers       int // адреса с открытыми потоками
+	BusiestPeer int // наибольшее количество потоков одного адреса
+}
+
+// считает открытые потоки сервера и каждого адреса
+type limiter struct {
+	cfg *config.Config
+	obs Observer
+
+	mu      sync.Mutex
+	streams int
+	peers   map[string]int
+}
+
+func newLimiter(cfg *config.Config, obs Observer) *limiter {
+	return &limiter{cfg: cfg, obs: obs, peers: make(map[string]int)}
+}
+
+// занимает место для потока адреса addr; при превышении ограничения возвращает RESOURCE_EXHAUSTED
+func (l *limiter) acquire(addr string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cfg.StreamLimit > 0 && uint(l.streams) >= l.cfg.StreamLimit {
+		l.obs.LimitExceeded(LimitStreams)
+		return status.Errorf(codes.ResourceExhausted, "server stream limit of %d reached", l.cfg.StreamLimit)
+	}
+	if l.cfg.PeerStreamLimit > 0 && uint(l.peers[addr]) >= l.cfg.PeerStreamLimit {
+		l.obs.LimitExceeded(LimitPeerStreams)
+		return status.Errorf(codes.ResourceExhausted, "stream limit of %d per peer reached", l.cfg.PeerStreamLimit)
+	}
+	l.streams++
+	l.peers[addr]++
+	l.obs.StreamsChanged(l.usage())
+	return nil
+}
+
+func (l *limiter) release(addr string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.streams--
+	if l.peers[addr]--; l.peers[addr] == 0 {
+		delete(l.peers, addr)
+	}
+	l.obs.StreamsChanged(l.usage())
+}
+
+// вызывается под l.mu
+func (l *limiter) usage() Usage {
+	u := Usage{Streams: l.streams, Peers: len(l.peers)}
+	for _, n := range l.peers {
+		u.BusiestPeer = max(u.BusiestPeer, n)
+	}
+	return u
+}
+
+// Usage возвращает текущее использование потоков
+func (s *Server) Usage() Usage {
+	s.limits.mu.Lock()
+	defer s.limits.mu.Unlock()
+
+	return s.limits.usage()
+}
+
+// ограничитель частоты: в среднем не больше rate событий в секунду, всплеск до burst
+type bucket struct {
+	rate, burst, tokens float64
+	last                time.Time
+}
+
+func newBucket(rate float64) *bucket {
+	burst := max(rate, 1) // за секунду допускается всплеск в rate сообщений
+	return &bucket{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+func (b *bucket) allow(now time.Time) bool {
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// адрес клиента без порта: ограничение на адрес действует для всех его соединений
+func peerHost(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,62 @@
+// github.com/lonmouth/alien_wave/server/internal/service/observer.go
+package service
+
+import (
+	"strings"
+	"time"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+)
+
+// причина удаления сессии, которую не завершило сообщение SessionEnd: поток отключился
+// и за время ожидания к сессии никто не подключился
+const ReasonExpired = "expired"
+
+// Observer получает показатели сервера; его реализует metrics.Prometheus
+type Observer interface {
+	StreamsChanged(u Usage)
+	LimitExceeded(limit string)
+	SessionStarted(id string, params *transmitter.GeneratorParameters)
+	SessionEnded(id, reason string) // reason - причина SessionEnd в нижнем регистре или ReasonExpired
+	MessageSent(kind string, latency time.Duration, err error)
+}
+
+type nopObserver struct{}
+
+func (nopObserver) StreamsChanged(Usage)                                    {}
+func (nopObserver) LimitExceeded(string)                                    {}
+func (nopObserver) SessionStarted(string, *transmitter.GeneratorParameters) {}
+func (nopObserver) SessionEnded(string, string)                             {}
+func (nopObserver) MessageSent(string, time.Duration, error)                {}
+
+// учитывает завершение сессии один раз, по первой причине
+func (s *Server) ended(sess *session, reason string) {
+	if sess.finish() {
+		s.obs.SessionEnded(sess.id, reason)
+	}
+}
+
+// ReasonLabel - причина SessionEnd для метрик: REASON_SERVER_SHUTDOWN - server_shutdown
+func ReasonLabel(reason transmitter.SessionEnd_Reason) string {
+	return strings.ToLower(strings.TrimPrefix(reason.String(), "REASON_"))
+}
+
+// вид отправленного сообщения для метрик: start, data, heartbeat или end
+func messageKind(m any) string {
+	switch m := m.(type) {
+	case *transmitter.Transmission: // StreamData
+		return "data"
+	case *transmitter.StreamMessage:
+		switch m.Payload.(type) {
+		case *transmitter.StreamMessage_Start:
+			return "start"
+		case *transmitter.StreamMessage_Data:
+			return "data"
+		case *transmitter.StreamMessage_Heartbeat:
+			return "heartbeat"
+		case *transmitter.StreamMessage_End:
+			return "end"
+		}
+	}
+	return "other"
+}
@@ -0,0 +1,86 @@
+// github.com/lonmouth/alien_wave/server/internal/service/registry.go
+package service
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// сессии сервера: отправляемые потоками, ожидающие переподключения Connect и созданные CreateSession
+type registry struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+func newRegistry() *registry {
+	return &registry{sessions: make(map[string]*session)}
+}
+
+func (r *registry) add(s *session) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sessions[s.id] = s
+}
+
+func (r *registry) get(id string) (*session, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.sessions[id]
+	return s, ok
+}
+
+func (r *registry) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.sessions, id)
+}
+
+// все сессии в порядке создания
+func (r *registry) list() []*session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := make([]*session, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].started.Before(list[j].started) })
+	return list
+}
+
+// минимальный интервал между точками, который может запросить клиент
+const minSendInterval = 10 * time.Millisecond
+
+// скорость отправки точек потока; клиент Connect меняет ее сообщениями Flow
+type flow struct {
+	mu       sync.Mutex
+	interval time.Duration
+	paused   bool
+	changed  chan struct{} // получает значение после каждого изменения
+}
+
+func newFlow(interval time.Duration) *flow {
+	return &flow{interval: interval, changed: make(chan struct{}, 1)}
+}
+
+func (f *flow) set(interval time.Duration, paused bool) {
+	f.mu.Lock()
+	f.interval, f.paused = interval, paused
+	f.mu.Unlock()
+
+	select {
+	case f.changed <- struct{}{}:
+	default: // предыдущее изменение еще не прочитано; run прочитает оба значения сразу
+	}
+}
+
+func (f *flow) get() (time.Duration, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.interval, f.paused
+}
@@ -0,0 +1,285 @@
+// github.com/lonmouth/alien_wave/server/internal/service/server.go
+package service
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"github.com/lonmouth/alien_wave/server/internal/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// реализация gRPC-сервиса передатчика
+type Server struct {
+	transmitter.UnimplementedTransmitterServiceServer
+	cfg      *config.Config
+	sessions *registry // все сессии сервера
+	limits   *limiter  // открытые потоки
+	obs      Observer
+	done     chan struct{} // закрывается при остановке сервера
+	once     sync.Once
+}
+
+// создает сервис; obs получает показатели сервера (nil - показатели не нужны)
+func New(cfg *config.Config, obs Observer) *Server {
+	if obs == nil {
+		obs = nopObserver{}
+	}
+	return &Server{cfg: cfg, sessions: newRegistry(), limits: newLimiter(cfg, obs), obs: obs, done: make(chan struct{})}
+}
+
+// Shutdown завершает сессии всех потоков с причиной REASON_SERVER_SHUTDOWN, после чего обработчики
+// возвращаются. Вызывается перед GracefulStop, иначе тот ждет отключения всех клиентов
+func (s *Server) Shutdown() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// поток одного клиента
+type conn struct {
+	ctx    context.Context
+	send   func(*transmitter.StreamMessage) error
+	flow   *flow
+	acks   bool          // клиент подтверждает точки: сессия хранит неподтвержденные
+	resume time.Duration // сколько сессия ждет нового потока после отключения этого
+}
+
+// StreamData отправляет только точки данных; события жизненного цикла сессии пропускаются
+func (s *Server) StreamData(
+	req *transmitter.Empty,
+	stream transmitter.TransmitterService_StreamDataServer, // поток для отправки данных
+) error {
+	return s.serve(&conn{
+		ctx: stream.Context(),
+		send: func(m *transmitter.StreamMessage) error {
+			if data := m.GetData(); data != nil {
+				return stream.Send(data)
+			}
+			return nil
+		},
+		flow: newFlow(s.cfg.SendInterval),
+	}, nil)
+}
+
+// Stream отправляет точки данных вместе с началом и концом сессии и heartbeat
+func (s *Server) Stream(req *transmitter.StreamRequest, stream transmitter.TransmitterService_StreamServer) error {
+	var sess *session
+	if req.SessionId != "" {
+		var ok bool
+		if sess, ok = s.sessions.get(req.SessionId); !ok {
+			return status.Errorf(codes.NotFound, "session %s not found or expired", req.SessionId)
+		}
+	}
+	return s.serve(&conn{ctx: stream.Context(), send: stream.Send, flow: newFlow(s.cfg.SendInterval)}, sess)
+}
+
+// Connect работает как Stream, но клиент подтверждает обработанные точки и управляет скоростью.
+// Неподтвержденные точки хранятся в сессии: после обрыва клиент может переподключиться
+// с Open{session_id, after_seq} в течение ResumeTimeout и получить их повторно
+func (s *Server) Connect(stream transmitter.TransmitterService_ConnectServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	open := first.GetOpen()
+	if open == nil {
+		return status.Error(codes.InvalidArgument, "first message must be open")
+	}
+
+	var sess *session
+	if open.SessionId != "" {
+		var ok bool
+		if sess, ok = s.sessions.get(open.SessionId); !ok {
+			return status.Errorf(codes.NotFound, "session %s not found or expired", open.SessionId)
+		}
+		sess.ack(open.AfterSeq) // точки до after_seq клиент уже обработал
+	}
+
+	c := &conn{ctx: stream.Context(), send: stream.Send, flow: newFlow(s.cfg.SendInterval), acks: true, resume: s.cfg.ResumeTimeout}
+	go s.receive(stream, c.flow)
+	return s.serve(c, sess)
+}
+
+// отправляет сессию sess (nil - новую со случайными параметрами), а после ее завершения - новые сессии,
+// пока клиент не отключится или сервер не остановится
+func (s *Server) serve(c *conn, sess *session) error {
+	// Создаем локальный генератор случайных чисел
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for {
+		if sess == nil {
+			var err error
+			if sess, err = s.newSession(randomParameters(s.cfg, r), r); err != nil {
+				return err
+			}
+		}
+		owner := sess.attach()
+		if owner == nil {
+			return status.Errorf(codes.NotFound, "session %s not found or expired", sess.id)
+		}
+		sess.active.Lock() // ждем, пока прежний поток сессии перестанет отправлять точки
+		done, err := s.run(c, sess, owner)
+		sess.active.Unlock()
+		if done || err != nil {
+			s.detach(c, sess, owner)
+			return err
+		}
+		sess.close() // сессия завершена и не может быть продолжена
+		s.sessions.remove(sess.id)
+		sess = nil
+	}
+}
+
+// открепляет сессию от закрытого потока: сессия ждет нового потока c.resume, после чего удаляется.
+// Пока у сессии есть подписчики, ее рассылает сам сервер
+func (s *Server) detach(c *conn, sess *session, owner chan struct{}) {
+	r := sess.release(owner, c.resume, func() {
+		s.sessions.remove(sess.id)
+		s.ended(sess, ReasonExpired)
+		if c.resume > 0 {
+			log.Printf("Session %s expired without resume", sess.id)
+		}
+	})
+	if r != nil {
+		go s.relay(sess, r)
+	}
+}
+
+// читает подтверждения и управление скоростью клиента до конца потока
+func (s *Server) receive(stream transmitter.TransmitterService_ConnectServer, fl *flow) {
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return
+		}
+		switch p := msg.Payload.(type) {
+		case *transmitter.ClientMessage_Ack:
+			if sess, ok := s.sessions.get(p.Ack.SessionId); ok {
+				sess.ack(p.Ack.Seq)
+			}
+		case *transmitter.ClientMessage_Flow:
+			interval := s.cfg.SendInterval
+			if p.Flow.SendInterval != nil {
+				interval = max(p.Flow.SendInterval.AsDuration(), minSendInterval, s.cfg.MinSendInterval())
+			}
+			fl.set(interval, p.Flow.Paused)
+			log.Printf("Flow changed: interval=%v, paused=%t", interval, p.Flow.Paused)
+		}
+	}
+}
+
+// создает сессию и добавляет ее в реестр
+func (s *Server) newSession(params *transmitter.GeneratorParameters, r *rand.Rand) (*session, error) {
+	sess, err := newSession(params, s.cfg.HeartbeatInterval, r)
+	if err != nil {
+		return nil, err
+	}
+	sess.interval = s.cfg.SendInterval
+	s.sessions.add(sess)
+	s.obs.SessionStarted(sess.id, params)
+	log.Printf("New session: %s (μ=%.2f, σ=%.2f, model=%s, channels=%d)", sess.id, params.Mean, params.Std, params.Model, len(sess.channels))
+	return sess, nil
+}
+
+// отправляет точки сессии до ее завершения. done - поток закончен и новая сессия не нужна.
+// owner закрывается, когда сессию забирает другой поток
+func (s *Server) run(c *conn, sess *session, owner <-chan struct{}) (done bool, err error) {
+	interval, paused := c.flow.get()
+	sess.setInterval(interval)
+	resumed := sess.lastSeq() > 0 // сессия уже отправляла точки другому потоку
+	if err := c.send(sess.start(interval, s.cfg.DiscloseParameters, resumed)); err != nil {
+		return true, err
+	}
+	if resumed && c.acks {
+		pending := sess.unacked()
+		for _, point := range pending {
+			if err := c.send(data(point)); err != nil {
+				return true, err
+			}
+		}
+		log.Printf("Session %s resumed: %d unacknowledged points resent", sess.id, len(pending))
+	}
+
+	// создаём тикер для регулярной отправки сообщений
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// точки, heartbeat и конец сессии получают и подписчики рассылки
+	send := func(m *transmitter.StreamMessage) error {
+		sess.publish(m)
+		return c.send(m)
+	}
+
+	// heartbeat отправляется, если с последнего сообщения прошло HeartbeatInterval
+	var heartbeat <-chan time.Time
+	if s.cfg.HeartbeatInterval > 0 {
+		timer := time.NewTimer(s.cfg.HeartbeatInterval)
+		defer timer.Stop()
+		heartbeat = timer.C
+		send = resetOnSend(timer, s.cfg.HeartbeatInterval, send)
+	}
+
+	// отправляет SessionEnd и учитывает завершение сессии
+	end := func(reason transmitter.SessionEnd_Reason, detail string) error {
+		s.ended(sess, ReasonLabel(reason))
+		return send(sess.end(reason, detail))
+	}
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			log.Printf("Session %s closed by client after %d points", sess.id, sess.lastSeq())
+			return true, nil
+		case <-s.done:
+			log.Printf("Session %s ended: server shutdown", sess.id)
+			return true, end(transmitter.SessionEnd_REASON_SERVER_SHUTDOWN, "server is shutting down")
+		case <-owner:
+			log.Printf("Session %s resumed by another stream", sess.id)
+			return true, nil
+		case <-sess.stopped:
+			log.Printf("Session %s ended by operator", sess.id)
+			return false, end(transmitter.SessionEnd_REASON_ENDED, sess.stopDetail())
+		case <-c.flow.changed:
+			next, p := c.flow.get()
+			if next != interval {
+				interval = next
+				ticker.Reset(interval)
+				sess.setInterval(interval)
+			}
+			paused = p
+		case now := <-heartbeat:
+			if err := send(sess.keepalive(now)); err != nil {
+				return true, err
+			}
+		case now := <-ticker.C:
+			// на паузе и при заполненном буфере неподтвержденных точек продолжается только heartbeat
+			if paused || (c.acks && uint(sess.unackedCount()) >= s.cfg.MaxUnacked) {
+				continue
+			}
+			// генерация значения частоты выбранной моделью распределения
+			point := sess.point(now)
+			if c.acks {
+				sess.hold(point)
+			}
+			if err := send(data(point)); err != nil {
+				return true, err
+			}
+			if s.cfg.SessionPoints > 0 && point.Seq >= s.cfg.SessionPoints {
+				log.Printf("Session %s ended: completed %d points", sess.id, point.Seq)
+				return false, end(transmitter.SessionEnd_REASON_COMPLETED, "")
+			}
+		}
+	}
+}
+
+// перезапускает таймер heartbeat после каждого отправленного сообщения
+func resetOnSend(timer *time.Timer, d time.Duration, send func(*transmitter.StreamMessage) error) func(*transmitter.StreamMessage) error {
+	return func(m *transmitter.StreamMessage) error {
+		timer.Reset(d)
+		return send(m)
+	}
+}
@@ -0,0 +1,367 @@
+// github.com/lonmouth/alien_wave/server/internal/service/session.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"github.com/lonmouth/alien_wave/server/internal/config"
+	"github.com/lonmouth/alien_wave/server/internal/generator"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// сессия генератора: параметры распределения и нумерация отправленных точек
+type session struct {
+	id        string
+	started   time.Time
+	params    *transmitter.GeneratorParameters // истинные параметры генератора
+	channels  []string                         // имена дополнительных каналов
+	next      func() []float64
+	heartbeat time.Duration
+	stopped   chan struct{} // закрывается EndSession
+	ended     atomic.Bool   // завершение сессии учтено в метриках
+
+	active  sync.Mutex // удерживается потоком, который отправляет точки сессии
+	mu      sync.Mutex
+	seq     uint64                      // номер последней отправленной точки
+	pending []*transmitter.Transmission // отправленные, но не подтвержденные точки (только сессии Connect)
+	owner   chan struct{}               // закрывается, когда сессию забирает другой поток (nil - поток отключился)
+	expiry  *time.Timer                 // удаляет сессию, если клиент не переподключился
+	closed  bool                        // сессия удалена из реестра и не может быть продолжена
+	detail  string                      // пояснение EndSession
+
+	interval  time.Duration            // интервал между точками потока, который отправляет сессию
+	subs      map[*subscriber]struct{} // подписчики рассылки
+	stopRelay context.CancelFunc       // останавливает рассылку сервера, когда уходит последний подписчик
+}
+
+// случайные параметры генератора из диапазонов конфигурации
+func randomParameters(cfg *config.Config, r *rand.Rand) *transmitter.GeneratorParameters {
+	// математическое ожидание (μ): среднее значение распределения
+	// стандартное отклонение (σ): мера разброса значений вокруг среднего
+	params := &transmitter.GeneratorParameters{
+		Model:              cfg.Generator,
+		Mean:               uniformIn(r, cfg.Mean), // по умолчанию [-10.0, 10.0]
+		Std:                uniformIn(r, cfg.STD),  // по умолчанию [0.3, 1.5]
+		ChannelCorrelation: cfg.ChannelCorrelation,
+	}
+	for i := range cfg.Channels {
+		params.Channels = append(params.Channels, &transmitter.ChannelParameters{
+			Name: fmt.Sprintf("ch%d", i+1),
+			Mean: uniformIn(r, cfg.Mean),
+			Std:  uniformIn(r, cfg.STD),
+		})
+	}
+	return params
+}
+
+// проверяет параметры CreateSession и дополняет значения по умолчанию: модель сервера и имена ch1..chN
+func checkParameters(params *transmitter.GeneratorParameters, cfg *config.Config) error {
+	if params.Model == "" {
+		params.Model = cfg.Generator
+	}
+	if !generator.Known(params.Model) {
+		return fmt.Errorf("unknown generator %q, expected one of %v", params.Model, generator.Models())
+	}
+	if params.Std <= 0 {
+		return fmt.Errorf("std must be positive, got %g", params.Std)
+	}
+	if len(params.Channels) > config.MaxChannels {
+		return fmt.Errorf("channels must be at most %d, got %d", config.MaxChannels, len(params.Channels))
+	}
+	if params.ChannelCorrelation < 0 || params.ChannelCorrelation >= 1 {
+		return fmt.Errorf("channel correlation must be in [0, 1), got %g", params.ChannelCorrelation)
+	}
+	names := make(map[string]bool, len(params.Channels))
+	for i, ch := range params.Channels {
+		if ch.Name == "" {
+			ch.Name = fmt.Sprintf("ch%d", i+1)
+		}
+		if names[ch.Name] {
+			return fmt.Errorf("duplicate channel name %q", ch.Name)
+		}
+		names[ch.Name] = true
+		if ch.Std <= 0 {
+			return fmt.Errorf("channel %s std must be positive, got %g", ch.Name, ch.Std)
+		}
+	}
+	return nil
+}
+
+// создает сессию с генератором заданных параметров
+func newSession(params *transmitter.GeneratorParameters, heartbeat time.Duration, r *rand.Rand) (*session, error) {
+	s := &session{
+		id:        uuid.New().String(), // генерация уникального ID сессии
+		started:   time.Now(),
+		params:    params,
+		heartbeat: heartbeat,
+		stopped:   make(chan struct{}),
+	}
+
+	// frequency - первый из коррелированных каналов; без дополнительных каналов генератор прежний
+	if len(params.Channels) == 0 {
+		gen, err := generator.New(params.Model, r, params.Mean, params.Std)
+		if err != nil {
+			return nil, err
+		}
+		s.next = func() []float64 { return []float64{gen.Next()} }
+		return s, nil
+	}
+	means, stds := []float64{params.Mean}, []float64{params.Std}
+	for _, ch := range params.Channels {
+		means = append(means, ch.Mean)
+		stds = append(stds, ch.Std)
+		s.channels = append(s.channels, ch.Name)
+	}
+	gen, err := generator.NewCorrelated(params.Model, r, means, stds, params.ChannelCorrelation)
+	if err != nil {
+		return nil, err
+	}
+	s.next = gen.Next
+	return s, nil
+}
+
+// генерирует следующую точку сессии
+func (s *session) point(now time.Time) *transmitter.Transmission {
+	values := s.next()
+	channels := make([]*transmitter.Channel, 0, len(s.channels))
+	for i, name := range s.channels {
+		channels = append(channels, &transmitter.Channel{Name: name, Value: values[i+1]})
+	}
+	s.mu.Lock()
+	s.seq++
+	seq := s.seq
+	s.mu.Unlock()
+	return &transmitter.Transmission{
+		SessionId:    s.id,
+		Frequency:    values[0],
+		Channels:     channels,
+		TimestampUtc: now.Unix(), // (int64) возвращает количество секунд, прошедших с начала эпохи Unix (1 января 1970 года, 00:00:00 UTC) до текущего момента времени
+		Timestamp:    timestamppb.New(now),
+		Seq:          seq,
+	}
+}
+
+// интервал между точками потока, который отправляет сессию
+func (s *session) sendInterval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.interval
+}
+
+func (s *session) setInterval(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.interval = interval
+}
+
+// номер последней отправленной точки
+func (s *session) lastSeq() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.seq
+}
+
+// сообщение о начале сессии; параметры генератора включаются, только если disclose.
+// resumed - сессия продолжается после переподключения
+func (s *session) start(interval time.Duration, disclose, resumed bool) *transmitter.StreamMessage {
+	start := &transmitter.SessionStart{
+		SessionId:         s.id,
+		StartedAt:         timestamppb.New(s.started),
+		SendInterval:      durationpb.New(interval),
+		HeartbeatInterval: durationpb.New(s.heartbeat),
+		Channels:          s.channels,
+		Resumed:           resumed,
+	}
+	if disclose {
+		start.Parameters = s.params
+	}
+	return &transmitter.StreamMessage{Payload: &transmitter.StreamMessage_Start{Start: start}}
+}
+
+func data(point *transmitter.Transmission) *transmitter.StreamMessage {
+	return &transmitter.StreamMessage{Payload: &transmitter.StreamMessage_Data{Data: point}}
+}
+
+func (s *session) keepalive(now time.Time) *transmitter.StreamMessage {
+	return &transmitter.StreamMessage{Payload: &transmitter.StreamMessage_Heartbeat{Heartbeat: &transmitter.Heartbeat{
+		SessionId: s.id,
+		Timestamp: timestamppb.New(now),
+		LastSeq:   s.lastSeq(),
+	}}}
+}
+
+func (s *session) end(reason transmitter.SessionEnd_Reason, detail string) *transmitter.StreamMessage {
+	return &transmitter.StreamMessage{Payload: &transmitter.StreamMessage_End{End: &transmitter.SessionEnd{
+		SessionId: s.id,
+		Reason:    reason,
+		Detail:    detail,
+		EndedAt:   timestamppb.Now(),
+		LastSeq:   s.lastSeq(),
+	}}}
+}
+
+// снимок состояния сессии для ListSessions и GetSession
+func (s *session) info() *transmitter.SessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return &transmitter.SessionInfo{
+		SessionId:   s.id,
+		StartedAt:   timestamppb.New(s.started),
+		LastSeq:     s.seq,
+		Unacked:     uint32(len(s.pending)),
+		Connected:   s.owner != nil,
+		Parameters:  s.params,
+		Subscribers: uint32(len(s.subs)),
+	}
+}
+
+// запоминает отправленную точку до подтверждения клиентом
+func (s *session) hold(point *transmitter.Transmission) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending = append(s.pending, point)
+}
+
+// забывает точки до seq включительно: клиент их обработал
+func (s *session) ack(seq uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := 0
+	for i < len(s.pending) && s.pending[i].Seq <= seq {
+		i++
+	}
+	s.pending = s.pending[i:]
+}
+
+// неподтвержденные точки в порядке отправки
+func (s *session) unacked() []*transmitter.Transmission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*transmitter.Transmission(nil), s.pending...)
+}
+
+func (s *session) unackedCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.pending)
+}
+
+// закрепляет сессию за новым потоком; предыдущий поток сессии завершается.
+// Возвращает nil, если сессия уже удалена
+func (s *session) attach() chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	if s.owner != nil {
+		close(s.owner)
+	}
+	if s.expiry != nil {
+		s.expiry.Stop()
+		s.expiry = nil
+	}
+	if s.stopRelay != nil {
+		s.stopRelay()
+		s.stopRelay = nil
+	}
+	s.owner = make(chan struct{})
+	return s.owner
+}
+
+// открепляет сессию от потока owner. Если за timeout к ней никто не подключится, вызывается expire.
+// Если у сессии остались подписчики, она сразу переходит к рассылке сервера: возвращается relay, который запускает вызывающий
+func (s *session) release(owner chan struct{}, timeout time.Duration, expire func()) *relay {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.owner != owner { // сессию уже забрал другой поток
+		return nil
+	}
+	if len(s.subs) > 0 {
+		return s.relay()
+	}
+	s.owner = nil
+	s.expiry = time.AfterFunc(timeout, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.owner == nil && !s.closed {
+			s.closed = true
+			expire()
+		}
+	})
+	return nil
+}
+
+// завершает сессию: продолжить ее после этого нельзя
+func (s *session) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	if s.expiry != nil {
+		s.expiry.Stop()
+		s.expiry = nil
+	}
+}
+
+// завершает сессию по запросу оператора. Сессию без потока закрывает сразу и возвращает true;
+// иначе ее поток отправит SessionEnd и закроет сессию сам
+func (s *session) stop(detail string) (closed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.stopped:
+		return s.closed // уже остановлена
+	default:
+	}
+	s.detail = detail
+	close(s.stopped)
+	if s.owner != nil {
+		return false
+	}
+	s.closed = true
+	if s.expiry != nil {
+		s.expiry.Stop()
+		s.expiry = nil
+	}
+	return true
+}
+
+// отмечает завершение сессии; true - только при первом вызове.
+// Не захватывает s.mu: вызывается и из expire, который выполняется под s.mu
+func (s *session) finish() bool {
+	return s.ended.CompareAndSwap(false, true)
+}
+
+// пояснение, с которым сессию завершил оператор
+func (s *session) stopDetail() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.detail
+}
+
+// возвращает случайное значение из диапазона [rng.Min, rng.Max]
+func uniformIn(r *rand.Rand, rng config.Range) float64 {
+	return rng.Min + r.Float64()*(rng.Max-rng.Min)
+}
@@ -1,82 +1,42 @@
-// Выполнять из папки `alien_wave/server/proto`
-// protoc --proto_path=proto \
-//        --go_out=proto \
-//        --go-grpc_out=proto \
-//        --go_opt=paths=source_relative \
-//        --go-grpc_opt=paths=source_relative \
-//        proto/transmitter.proto
-// Указывает, где искать .proto файлы (относительно текущей директории)
-// --go_out=proto/gen: Генерировать .pb.go файлы в proto/gen
-// --go_opt=paths=source_relative: Сохраняет структуру директорий исходного .proto файла
+// API передатчика описан в общем модуле `alien_wave/api`, который импортируют клиент и сервер.
+// Выполнять из папки `alien_wave/api`
+// make generate
+// buf lint: проверяет стиль контракта (правила в buf.yaml)
+// buf breaking: сравнивает контракт с основной веткой (origin/HEAD или main) и отклоняет несовместимые изменения
+// buf generate: генерирует .pb.go файлы рядом с .proto (плагины в buf.gen.yaml)
 
 // github.com/lonmouth/alien_wave/server
 package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
-	"math/rand"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
-	"time"
 
-	"github.com/google/uuid"
-	transmitter "github.com/lonmouth/alien_wave/server/proto"
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"github.com/lonmouth/alien_wave/server/internal/config"
+	"github.com/lonmouth/alien_wave/server/internal/metrics"
+	"github.com/lonmouth/alien_wave/server/internal/service"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
 )
 
-const (
-	port          = ":50051"        // Порт, который будет слушать сервер
-	sendInterval  = 1 * time.Second // интервал между сообщениями
-	serverTimeout = 5 * time.Second // Таймаут для операций сервера
-)
-
-type Server struct {
-	transmitter.UnimplementedTransmitterServiceServer
-}
-
-func (s *Server) StreamData(
-	req *transmitter.Empty,
-	stream transmitter.TransmitterService_StreamDataServer, // поток для отправки данных
-) error {
-	// генерация параметров распределения
-	// Создаем локальный генератор случайных чисел
-	src := rand.NewSource(time.Now().UnixNano())
-	r := rand.New(src)
-	// математическое ожидание (μ): среднее значение распределения
-	mean := r.Float64()*20 - 10 // [-10.0, 10.0]
-	// стандартное отклонение (σ): мера разброса значений вокруг среднего
-	std := r.Float64()*1.2 + 0.3     // [0.3, 1.5]
-	sessionID := uuid.New().String() // генерация уникального ID сессии
-
-	log.Printf("New session: %s (μ=%.2f, σ=%.2f)", sessionID, mean, std)
-
-	// создаём тикер для регулярной отправки сообщений
-	ticker := time.NewTicker(sendInterval)
-	defer ticker.Stop()
-
-	// бесконечный цикл генерации данных
-	for {
-		select {
-		case <-stream.Context().Done():
-			return nil
-		case <-ticker.C:
-			// // генерация значения частоты с нормальным распределением
-			frequency := rand.NormFloat64()*std + mean // NormFloat64 генерирует случайное число с плавающей точкой, которое следует нормальному распределению (характеризуется "колоколообразной" кривой) со средним значением 0 и стандартным отклонением 1
-			err := stream.Send(&transmitter.Transmission{
-				SessionId:    sessionID,
-				Frequency:    frequency,
-				TimestampUtc: time.Now().Unix(), // (int64) возвращает количество секунд, прошедших с начала эпохи Unix (1 января 1970 года, 00:00:00 UTC) до текущего момента времени
-			})
-			if err != nil {
-				return err
-			}
-		}
+func main() {
+	// загружаем конфигурацию: значения по умолчанию, YAML-файл, окружение, флаги
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) { // справка по флагам уже выведена
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
 	}
-}
 
-func main() {
 	// настраиваем перехват сигналов прерывания (Ctrl+C)
 	ctx, stop := signal.NotifyContext(
 		context.Background(),
@@ -84,29 +44,69 @@ func main() {
 	)
 	defer stop() // восстанавливаем стандартное поведение сигналов при выходе
 
-	// cоздаем TCP-листенер для указанного порта
-	lis, err := net.Listen("tcp", port)
+	// cоздаем TCP-листенер для указанного адреса
+	lis, err := net.Listen("tcp", cfg.ListenAddr)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
+	// показатели сервера; без METRICS_ADDR не собираются
+	var (
+		observer      service.Observer
+		metricsServer *http.Server
+	)
+	if cfg.MetricsAddr != "" {
+		m := metrics.New(cfg)
+		observer = m
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", m.Handler())
+		metricsServer = &http.Server{Addr: cfg.MetricsAddr, Handler: mux}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("Metrics server error: %v", err)
+			}
+		}()
+		log.Printf("Metrics are served on %s/metrics", cfg.MetricsAddr)
+	}
+	srv := service.New(cfg, observer)
+
 	// создаем экземпляр gRPC-сервера с настройками
 	s := grpc.NewServer(
-		grpc.ConnectionTimeout(serverTimeout), // таймаут для соединений
+		grpc.ConnectionTimeout(cfg.ConnectionTimeout), // таймаут для соединений
+		grpc.MaxConcurrentStreams(cfg.MaxConcurrentStreams),
+		grpc.StreamInterceptor(srv.StreamInterceptor()), // ограничения количества, длительности и частоты потоков
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:                  cfg.Keepalive.Time,
+			Timeout:               cfg.Keepalive.Timeout,
+			MaxConnectionIdle:     cfg.Keepalive.MaxConnectionIdle,
+			MaxConnectionAge:      cfg.Keepalive.MaxConnectionAge,
+			MaxConnectionAgeGrace: cfg.Keepalive.MaxConnectionAgeGrace,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.Keepalive.MinTime,
+			PermitWithoutStream: cfg.Keepalive.PermitWithoutStream,
+		}),
 	)
 	// регистрируем наш сервис на сервере
-	transmitter.RegisterTransmitterServiceServer(s, &Server{})
+	transmitter.RegisterTransmitterServiceServer(s, srv)
+	if cfg.Reflection {
+		reflection.Register(s) // описание сервиса для grpcurl и подобных инструментов
+		log.Println("gRPC reflection is enabled")
+	}
 
 	// запускаем горутину для обработки graceful shutdown
 	go func() {
 		<-ctx.Done() // ожидаем сигнал завершения
 		log.Println("Shutting down server...")
+		srv.Shutdown()   // завершаем сессии, чтобы клиенты узнали причину
 		s.GracefulStop() // плавная остановка сервера
+		if metricsServer != nil {
+			metricsServer.Close()
+		}
 	}()
 
 	// запускаем сервер и логируем статус
-	log.Printf("Server is running on port %s...", port)
-	// reflection.Register(s)
+	log.Printf("Server is running on %s...", cfg.ListenAddr)
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
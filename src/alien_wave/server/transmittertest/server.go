@@ -0,0 +1,107 @@
+// github.com/lonmouth/alien_wave/server/transmittertest/server.go
+
+// Package transmittertest запускает настоящий сервис передатчика в памяти процесса (bufconn),
+// чтобы тесты клиента проверяли весь путь данных без сетевого порта
+package transmittertest
+
+import (
+	"context"
+	"net"
+
+	transmitter "github.com/lonmouth/alien_wave/api/transmitter"
+	"github.com/lonmouth/alien_wave/server/internal/config"
+	"github.com/lonmouth/alien_wave/server/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// Target - адрес сервера для grpc.NewClient вместе с DialOptions
+const Target = "passthrough:///bufconn"
+
+// размер буфера соединения bufconn
+const bufSize = 1 << 20
+
+// Inject изменяет точку перед отправкой клиенту, например подменяет частоту точки с заданным seq.
+// Получает копию: точки, хранимые сессией для повторной отправки, не меняются
+type Inject func(point *transmitter.Transmission)
+
+// Server - сервис передатчика на bufconn
+type Server struct {
+	lis  *bufconn.Listener
+	grpc *grpc.Server
+	srv  *service.Server
+}
+
+// NewServer запускает сервис с конфигурацией из флагов args, как у бинарника сервера. Переменные окружения
+// и CONFIG_FILE не читаются, чтобы окружение разработчика не влияло на тесты.
+// inject (может быть nil) применяется к каждой точке данных
+func NewServer(args []string, inject Inject) (*Server, error) {
+	cfg, err := config.FromFlags(args)
+	if err != nil {
+		return nil, err
+	}
+	srv := service.New(cfg, nil)
+	interceptors := []grpc.StreamServerInterceptor{srv.StreamInterceptor()}
+	if inject != nil {
+		interceptors = append(interceptors, injector(inject))
+	}
+	s := &Server{
+		lis:  bufconn.Listen(bufSize),
+		grpc: grpc.NewServer(grpc.ChainStreamInterceptor(interceptors...)),
+		srv:  srv,
+	}
+	transmitter.RegisterTransmitterServiceServer(s.grpc, srv)
+	go s.grpc.Serve(s.lis) // после Close возвращает ошибку закрытого listener
+	return s, nil
+}
+
+// DialOptions подключают клиент к серверу через bufconn
+func (s *Server) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+}
+
+// Dial открывает соединение с сервером, например для унарных RPC управления сессиями
+func (s *Server) Dial() (*grpc.ClientConn, error) {
+	return grpc.NewClient(Target, s.DialOptions()...)
+}
+
+// Close завершает сессии с причиной REASON_SERVER_SHUTDOWN и останавливает сервер
+func (s *Server) Close() {
+	s.srv.Shutdown()
+	s.grpc.Stop()
+}
+
+// применяет inject к копиям отправляемых точек
+func injector(inject Inject) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &injectStream{ServerStream: ss, inject: inject})
+	}
+}
+
+type injectStream struct {
+	grpc.ServerStream
+	inject Inject
+}
+
+func (s *injectStream) SendMsg(m any) error {
+	switch msg := m.(type) {
+	case *transmitter.Transmission: // StreamData
+		point := proto.Clone(msg).(*transmitter.Transmission)
+		s.inject(point)
+		m = point
+	case *transmitter.StreamMessage:
+		if msg.GetData() != nil {
+			cp := proto.Clone(msg).(*transmitter.StreamMessage)
+			s.inject(cp.GetData())
+			m = cp
+		}
+	}
+	return s.ServerStream.SendMsg(m)
+}